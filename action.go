@@ -0,0 +1,113 @@
+package streamdeck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+// Action is a Handler of an action declared in the manifest.
+// The manifest entry and the handler are defined together so that the UUID
+// does not drift between manifest.json and the runtime.
+type Action interface {
+	Handler
+	// ManifestAction returns the entry of the action in manifest.json.
+	ManifestAction() manifest.Action
+}
+
+// NewAction returns an Action of the manifest entry handled by h.
+func NewAction(ma manifest.Action, h Handler) Action {
+	return &action{ma, h}
+}
+
+type action struct {
+	manifest manifest.Action
+	Handler
+}
+
+func (a *action) ManifestAction() manifest.Action {
+	return a.manifest
+}
+
+// ErrUndeclaredAction is passed to the function set by ActionMux.OnUndeclared
+// with an event of an action which is not registered.
+var ErrUndeclaredAction = errors.New("undeclared action")
+
+// ActionMux routes events to the Action of the event's ActionID.
+// Events without ActionID, e.g. DeviceDidConnect, are routed to the handler
+// set by HandleGlobal. Events of undeclared actions are dropped unless
+// OnUndeclared is set.
+type ActionMux struct {
+	actions    []Action
+	byID       map[ActionID]Action
+	global     Handler
+	undeclared func(ctx context.Context, ev Event, err error) error
+}
+
+// NewActionMux returns an ActionMux routing events to the actions.
+// It returns an error if the UUIDs of the actions are empty or duplicated.
+func NewActionMux(actions ...Action) (*ActionMux, error) {
+	m := &ActionMux{byID: make(map[ActionID]Action, len(actions))}
+	for _, a := range actions {
+		id := ActionID(a.ManifestAction().UUID)
+		if id == "" {
+			return nil, fmt.Errorf("action has no UUID: %T", a)
+		}
+		if _, ok := m.byID[id]; ok {
+			return nil, fmt.Errorf("duplicated action: %s", id)
+		}
+		m.byID[id] = a
+		m.actions = append(m.actions, a)
+	}
+	return m, nil
+}
+
+// HandleGlobal sets the handler of the events without ActionID.
+// Such events are ignored if no handler is set.
+func (m *ActionMux) HandleGlobal(h Handler) {
+	m.global = h
+}
+
+// OnUndeclared sets the function called with an event of an undeclared action
+// and the error wrapping ErrUndeclaredAction, e.g. to log the event.
+// The returned error is returned by Handle, so return err to stop
+// SDK.Receive on such an event.
+func (m *ActionMux) OnUndeclared(f func(ctx context.Context, ev Event, err error) error) {
+	m.undeclared = f
+}
+
+// Actions returns the registered actions in the registered order.
+func (m *ActionMux) Actions() []Action {
+	return append([]Action(nil), m.actions...)
+}
+
+// ManifestActions returns the manifest entries of the registered actions.
+func (m *ActionMux) ManifestActions() []manifest.Action {
+	as := make([]manifest.ActionSource, len(m.actions))
+	for i, a := range m.actions {
+		as[i] = a
+	}
+	return manifest.FromActions(as...)
+}
+
+// Handle implements Handler.
+func (m *ActionMux) Handle(ctx context.Context, ev Event) error {
+	id, ok := actionIDOf(ev)
+	if !ok {
+		if m.global == nil {
+			return nil
+		}
+		return m.global.Handle(ctx, ev)
+	}
+
+	a, ok := m.byID[id]
+	if !ok {
+		if m.undeclared == nil {
+			return nil
+		}
+		return m.undeclared(ctx, ev, fmt.Errorf("%w: %s", ErrUndeclaredAction, id))
+	}
+	return a.Handle(ctx, ev)
+}
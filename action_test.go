@@ -0,0 +1,69 @@
+package streamdeck
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+func TestActionMux(t *testing.T) {
+	var got []string
+	record := func(name string) Handler {
+		return HandlerFunc(func(ctx context.Context, ev Event) error {
+			got = append(got, name)
+			return nil
+		})
+	}
+
+	mux, err := NewActionMux(
+		NewAction(manifest.Action{Name: "A", UUID: "com.example.a"}, record("a")),
+		NewAction(manifest.Action{Name: "B", UUID: "com.example.b"}, record("b")),
+	)
+	noError(t, err)
+	mux.HandleGlobal(record("global"))
+
+	ctx := context.Background()
+	noError(t, mux.Handle(ctx, &KeyDown{Action: "com.example.b"}))
+	noError(t, mux.Handle(ctx, &WillAppear{Action: "com.example.a"}))
+	noError(t, mux.Handle(ctx, &SystemDidWakeUp{}))
+	equal(t, got, []string{"b", "a", "global"})
+
+	// events of undeclared actions are dropped by default.
+	noError(t, mux.Handle(ctx, &KeyDown{Action: "com.example.c"}))
+	equal(t, got, []string{"b", "a", "global"})
+
+	mux.OnUndeclared(func(ctx context.Context, ev Event, err error) error {
+		return err
+	})
+	err = mux.Handle(ctx, &KeyDown{Action: "com.example.c"})
+	if !errors.Is(err, ErrUndeclaredAction) {
+		t.Errorf("want ErrUndeclaredAction but got %v", err)
+	}
+
+	equal(t, mux.ManifestActions(), []manifest.Action{
+		{Name: "A", UUID: "com.example.a"},
+		{Name: "B", UUID: "com.example.b"},
+	})
+}
+
+func TestNewActionMux_Invalid(t *testing.T) {
+	h := HandlerFunc(func(ctx context.Context, ev Event) error { return nil })
+	for name, actions := range map[string][]Action{
+		"no uuid": {
+			NewAction(manifest.Action{Name: "A"}, h),
+		},
+		"duplicated": {
+			NewAction(manifest.Action{UUID: "com.example.a"}, h),
+			NewAction(manifest.Action{UUID: "com.example.a"}, h),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewActionMux(actions...)
+			if err == nil {
+				t.Error("want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package streamdeck
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"sync"
+	"time"
+)
+
+// Frame is an image of an animation shown for Delay.
+type Frame struct {
+	Image Image
+	Delay time.Duration
+}
+
+// Animation is a sequence of frames shown on a key.
+type Animation struct {
+	Frames []Frame
+	// LoopCount is the number of times to play the frames. 0 means forever.
+	LoopCount int
+}
+
+// NewAnimation returns an Animation looping the images forever at the same interval.
+func NewAnimation(delay time.Duration, images ...Image) *Animation {
+	frames := make([]Frame, len(images))
+	for i, img := range images {
+		frames[i] = Frame{Image: img, Delay: delay}
+	}
+	return &Animation{Frames: compactFrames(frames)}
+}
+
+// DecodeGIF decodes a GIF image into an Animation of the size.
+func DecodeGIF(r io.Reader, size ImageSize) (*Animation, error) {
+	g, err := gif.DecodeAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GIF: %w", err)
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	canvas := image.NewNRGBA(bounds)
+	frames := make([]Frame, 0, len(g.Image))
+	for i, src := range g.Image {
+		var previous *image.NRGBA
+		if len(g.Disposal) > i && g.Disposal[i] == gif.DisposalPrevious {
+			previous = image.NewNRGBA(bounds)
+			draw.Draw(previous, bounds, canvas, image.Point{}, draw.Src)
+		}
+
+		draw.Draw(canvas, src.Bounds(), src, src.Bounds().Min, draw.Over)
+		img, err := NewImageFromImage(canvas, size)
+		if err != nil {
+			return nil, err
+		}
+
+		delay := 100 * time.Millisecond
+		if len(g.Delay) > i && g.Delay[i] > 1 {
+			delay = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+		frames = append(frames, Frame{Image: img, Delay: delay})
+
+		if len(g.Disposal) > i {
+			switch g.Disposal[i] {
+			case gif.DisposalBackground:
+				draw.Draw(canvas, src.Bounds(), image.Transparent, image.Point{}, draw.Src)
+			case gif.DisposalPrevious:
+				canvas = previous
+			}
+		}
+	}
+
+	anim := &Animation{Frames: compactFrames(frames)}
+	switch {
+	case g.LoopCount < 0:
+		anim.LoopCount = 1
+	case g.LoopCount > 0:
+		anim.LoopCount = g.LoopCount + 1
+	}
+	return anim, nil
+}
+
+// compactFrames merges consecutive identical frames into one.
+func compactFrames(frames []Frame) []Frame {
+	var compacted []Frame
+	for _, f := range frames {
+		if n := len(compacted); n > 0 && compacted[n-1].Image == f.Image {
+			compacted[n-1].Delay += f.Delay
+			continue
+		}
+		compacted = append(compacted, f)
+	}
+	return compacted
+}
+
+// Animator plays animations on keys by calling SetImage per frame.
+// The frame rate is capped so as not to flood the connection, and
+// a frame identical to the previous one is not sent.
+//
+// An animation keeps playing after its key disappears unless the handler
+// is wrapped with Middleware or Stop is called on WillDisappear.
+type Animator struct {
+	sdk         *SDK
+	minInterval time.Duration
+	afterFunc   func(d time.Duration, f func()) timer
+
+	mu      sync.Mutex
+	players map[InstanceID]*player
+}
+
+type player struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAnimator returns an Animator sending at most maxFPS frames per second per instance.
+func NewAnimator(sdk *SDK, maxFPS int) *Animator {
+	if maxFPS <= 0 {
+		maxFPS = 1
+	}
+	return &Animator{
+		sdk:         sdk,
+		minInterval: time.Second / time.Duration(maxFPS),
+		afterFunc:   afterFunc,
+		players:     make(map[InstanceID]*player),
+	}
+}
+
+// Play starts the animation on the instance replacing the playing one.
+func (a *Animator) Play(id InstanceID, anim *Animation, target Target, state int) {
+	a.Stop(id)
+	if len(anim.Frames) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &player{cancel: cancel, done: make(chan struct{})}
+
+	a.mu.Lock()
+	a.players[id] = p
+	a.mu.Unlock()
+
+	go func() {
+		defer close(p.done)
+		defer a.remove(id, p)
+
+		err := a.play(ctx, id, anim, target, state)
+		if err != nil {
+			a.sdk.Log("go-stream-deck-sdk: animation stopped:", err)
+		}
+	}()
+}
+
+func (a *Animator) play(ctx context.Context, id InstanceID, anim *Animation, target Target, state int) error {
+	var last Image
+
+	for loop := 0; anim.LoopCount == 0 || loop < anim.LoopCount; loop++ {
+		for _, f := range anim.Frames {
+			if f.Image != last {
+				err := a.sdk.SetImage(id, f.Image, target, state)
+				if err != nil {
+					return err
+				}
+				last = f.Image
+			}
+
+			delay := f.Delay
+			if delay < a.minInterval {
+				delay = a.minInterval
+			}
+			fired := make(chan struct{})
+			timer := a.afterFunc(delay, func() { close(fired) })
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			case <-fired:
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *Animator) remove(id InstanceID, p *player) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.players[id] == p {
+		delete(a.players, id)
+	}
+}
+
+// Playing reports whether an animation is playing on the instance.
+func (a *Animator) Playing(id InstanceID) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, ok := a.players[id]
+	return ok
+}
+
+// Stop stops the animation on the instance and waits for it to finish.
+// The last frame is left on the key.
+func (a *Animator) Stop(id InstanceID) {
+	a.mu.Lock()
+	p, ok := a.players[id]
+	delete(a.players, id)
+	a.mu.Unlock()
+
+	if ok {
+		p.cancel()
+		<-p.done
+	}
+}
+
+// StopAll stops all animations.
+func (a *Animator) StopAll() {
+	a.mu.Lock()
+	ids := make([]InstanceID, 0, len(a.players))
+	for id := range a.players {
+		ids = append(ids, id)
+	}
+	a.mu.Unlock()
+
+	for _, id := range ids {
+		a.Stop(id)
+	}
+}
+
+// Middleware stops the animation when the instance disappears.
+// Without it, the animation of a disappeared instance keeps sending frames.
+func (a *Animator) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, ev Event) error {
+			if ev, ok := ev.(*WillDisappear); ok {
+				a.Stop(ev.Context)
+			}
+			return next.Handle(ctx, ev)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package streamdeck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+	"time"
+)
+
+func TestAnimator_Play(t *testing.T) {
+	sdk, srv := newTestSDK(t)
+	a := NewAnimator(sdk, 1000)
+	clock := &fakeClock{}
+	timers := make(chan struct{}, 1)
+	a.afterFunc = func(d time.Duration, f func()) timer {
+		t := clock.afterFunc(d, f)
+		timers <- struct{}{}
+		return t
+	}
+
+	a.Play("context", &Animation{
+		Frames: []Frame{
+			{Image: "a", Delay: time.Millisecond},
+			{Image: "a", Delay: time.Millisecond},
+			{Image: "b", Delay: time.Millisecond},
+		},
+		LoopCount: 1,
+	}, TargetBoth, 0)
+	a.mu.Lock()
+	done := a.players["context"].done
+	a.mu.Unlock()
+
+	expectImage := func(want Image) {
+		t.Helper()
+
+		cmd := nextCommand(t, srv)
+		equal(t, cmd.Event, "setImage")
+		equal(t, cmd.Context, "context")
+		var got SetImage
+		noError(t, json.Unmarshal(cmd.Payload, &got))
+		equal(t, got.Image, want)
+	}
+	// next waits for the delay of the current frame to start and ends it.
+	next := func() {
+		t.Helper()
+
+		select {
+		case <-timers:
+		case <-time.After(time.Second):
+			t.Fatal("timeout")
+		}
+		clock.advance(time.Millisecond)
+	}
+
+	expectImage("a")
+	next()
+	next()
+	expectImage("b")
+	next()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("animation must stop after the loop")
+	}
+	equal(t, a.Playing("context"), false)
+
+	_, err := srv.NextCommand(10 * time.Millisecond)
+	if err == nil {
+		t.Fatal("identical frames must not be sent")
+	}
+}
+
+func TestAnimator_Middleware(t *testing.T) {
+	sdk, _ := newTestSDK(t)
+	a := NewAnimator(sdk, 10)
+	a.Play("context", NewAnimation(time.Millisecond, "a", "b"), TargetBoth, 0)
+	equal(t, a.Playing("context"), true)
+
+	h := Chain(HandlerFunc(func(ctx context.Context, ev Event) error {
+		return nil
+	}), a.Middleware())
+	noError(t, h.Handle(context.Background(), &WillDisappear{Context: "context"}))
+
+	equal(t, a.Playing("context"), false)
+}
+
+func TestDecodeGIF(t *testing.T) {
+	palette := color.Palette{color.Black, color.White}
+	frame := func(c uint8) *image.Paletted {
+		img := image.NewPaletted(image.Rect(0, 0, 4, 4), palette)
+		for i := range img.Pix {
+			img.Pix[i] = c
+		}
+		return img
+	}
+
+	var buf bytes.Buffer
+	noError(t, gif.EncodeAll(&buf, &gif.GIF{
+		Image:     []*image.Paletted{frame(0), frame(0), frame(1)},
+		Delay:     []int{10, 20, 0},
+		LoopCount: -1,
+	}))
+
+	anim, err := DecodeGIF(&buf, ImageSizeKey)
+	noError(t, err)
+
+	equal(t, anim.LoopCount, 1)
+	equal(t, len(anim.Frames), 2)
+	equal(t, anim.Frames[0].Delay, 300*time.Millisecond)
+	equal(t, anim.Frames[1].Delay, 100*time.Millisecond)
+}
@@ -0,0 +1,227 @@
+package bundle
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+const (
+	// ArchiveExtension is the extension of the distributable plugin archive.
+	ArchiveExtension = ".streamDeckPlugin"
+	// PluginDirExtension is the extension of the plugin directory.
+	PluginDirExtension = ".sdPlugin"
+)
+
+// DefaultExcludes is the patterns of the files excluded from the archive.
+// A pattern matches the base name of a file or a directory.
+var DefaultExcludes = []string{
+	".*",
+	"Thumbs.db",
+	"logs",
+	"*.log",
+	"*.go",
+	"*.map",
+}
+
+// archiveTime is the modification time of all entries for reproducible archives.
+var archiveTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type ArchiveOption archiveOption
+
+// WithExclude adds the patterns of the files excluded from the archive
+// in addition to DefaultExcludes. See path.Match for the syntax.
+func WithExclude(patterns ...string) ArchiveOption {
+	return func(c *archiveConfig) {
+		c.excludes = append(c.excludes, patterns...)
+	}
+}
+
+// WithoutIconCheck skips CheckIcons before archiving.
+func WithoutIconCheck() ArchiveOption {
+	return func(c *archiveConfig) {
+		c.skipIconCheck = true
+	}
+}
+
+type archiveOption func(*archiveConfig)
+
+type archiveConfig struct {
+	excludes      []string
+	skipIconCheck bool
+}
+
+// Archive writes the .streamDeckPlugin archive of the plugin directory,
+// which is a zip file containing the .sdPlugin directory.
+// The plugin is validated first, and the error is manifest.Problems if it
+// is invalid. The archive is reproducible: the entries are sorted and have
+// the fixed modification time.
+func Archive(w io.Writer, pluginDir string, opts ...ArchiveOption) error {
+	cfg := archiveConfig{excludes: append([]string(nil), DefaultExcludes...)}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	root := filepath.Base(filepath.Clean(pluginDir))
+	if !strings.HasSuffix(root, PluginDirExtension) {
+		return fmt.Errorf("plugin directory must have .sdPlugin extension: %s", pluginDir)
+	}
+
+	m, err := manifest.Load(filepath.Join(pluginDir, "manifest.json"))
+	if err != nil {
+		return err
+	}
+	ps := m.ValidateDir(pluginDir)
+	if !cfg.skipIconCheck {
+		ps = append(ps, CheckIcons(pluginDir, m)...)
+	}
+	if err := ps.Err(); err != nil {
+		return err
+	}
+
+	files, err := archiveFiles(pluginDir, cfg.excludes)
+	if err != nil {
+		return err
+	}
+
+	// The binaries are executable even if archived on Windows,
+	// where files have no executable bit.
+	executables := map[string]bool{m.CodePath: true}
+	if m.CodePathMac != nil {
+		executables[*m.CodePathMac] = true
+	}
+
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		if executables[f.rel] {
+			f.mode |= 0o111
+		}
+		err := writeEntry(zw, pluginDir, root, f)
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// ArchiveFile writes the archive of the plugin directory into outDir,
+// and returns the path of the archive, e.g. outDir/com.example.plugin.streamDeckPlugin.
+// outDir is created if it does not exist.
+func ArchiveFile(pluginDir, outDir string, opts ...ArchiveOption) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(filepath.Clean(pluginDir)), PluginDirExtension) + ArchiveExtension
+	out := filepath.Join(outDir, name)
+
+	err := os.MkdirAll(outDir, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive: %w", err)
+	}
+	err = Archive(f, pluginDir, opts...)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(out)
+		return "", err
+	}
+	err = f.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	return out, nil
+}
+
+type archiveEntry struct {
+	rel  string
+	mode fs.FileMode
+}
+
+func archiveFiles(dir string, excludes []string) ([]archiveEntry, error) {
+	var files []archiveEntry
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		for _, pattern := range excludes {
+			if ok, _ := path.Match(pattern, d.Name()); ok {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+		files = append(files, archiveEntry{filepath.ToSlash(rel), info.Mode()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plugin files: %w", err)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].rel < files[j].rel
+	})
+	return files, nil
+}
+
+func writeEntry(zw *zip.Writer, dir, root string, e archiveEntry) error {
+	h := &zip.FileHeader{
+		Name:     root + "/" + e.rel,
+		Method:   zip.Deflate,
+		Modified: archiveTime,
+	}
+	if e.mode.IsDir() {
+		h.Name += "/"
+		h.Method = zip.Store
+		h.SetMode(fs.ModeDir | 0o755)
+		_, err := zw.CreateHeader(h)
+		return err
+	}
+
+	// Keep only the executable bit so that the result does not depend on umask.
+	if e.mode&0o111 != 0 {
+		h.SetMode(0o755)
+	} else {
+		h.SetMode(0o644)
+	}
+	w, err := zw.CreateHeader(h)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(filepath.Join(dir, filepath.FromSlash(e.rel)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", e.rel, err)
+	}
+	return nil
+}
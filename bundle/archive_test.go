@@ -0,0 +1,151 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+func pngOf(t *testing.T, w, h int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// writePlugin writes a valid plugin directory with the icons of the required sizes.
+func writePlugin(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "com.example.plugin.sdPlugin")
+	all := map[string]string{
+		"manifest.json":        testManifest,
+		"plugin":               "mac",
+		"plugin.exe":           "windows",
+		"images/icon.png":      pngOf(t, 72, 72),
+		"images/icon@2x.png":   pngOf(t, 144, 144),
+		"images/action.svg":    "<svg/>",
+		"pi/index.html":        "<html></html>",
+		".DS_Store":            "",
+		"logs/plugin.log":      "log",
+		"main.go":              "package main",
+		"pi/index.js.map":      "{}",
+		"pi/.git/HEAD":         "ref",
+		"propertyinspector.js": "",
+	}
+	for k, v := range files {
+		all[k] = v
+	}
+	writeFiles(t, dir, all)
+
+	m, err := manifest.Load(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.CodePathMac = manifest.OptionalString("plugin")
+	m.CodePathWin = manifest.OptionalString("plugin.exe")
+	err = writeManifest(filepath.Join(dir, "manifest.json"), m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestArchive(t *testing.T) {
+	dir := writePlugin(t, nil)
+
+	var first, second bytes.Buffer
+	if err := Archive(&first, dir); err != nil {
+		t.Fatal(err)
+	}
+	// Touch a file to check the archive does not depend on the time.
+	if err := os.Chtimes(filepath.Join(dir, "plugin"), archiveTime.AddDate(40, 0, 0), archiveTime.AddDate(40, 0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := Archive(&second, dir); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Error("archive is not reproducible")
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(first.Bytes()), int64(first.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		if !f.Modified.Equal(archiveTime) {
+			t.Errorf("%s: unexpected time: %v", f.Name, f.Modified)
+		}
+		if f.Name == "com.example.plugin.sdPlugin/plugin" && f.Mode().Perm() != 0o755 {
+			t.Errorf("binary is not executable: %v", f.Mode())
+		}
+	}
+	want := []string{
+		"com.example.plugin.sdPlugin/images/",
+		"com.example.plugin.sdPlugin/images/action.svg",
+		"com.example.plugin.sdPlugin/images/icon.png",
+		"com.example.plugin.sdPlugin/images/icon@2x.png",
+		"com.example.plugin.sdPlugin/manifest.json",
+		"com.example.plugin.sdPlugin/pi/",
+		"com.example.plugin.sdPlugin/pi/index.html",
+		"com.example.plugin.sdPlugin/plugin",
+		"com.example.plugin.sdPlugin/plugin.exe",
+		"com.example.plugin.sdPlugin/propertyinspector.js",
+	}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestArchive_Invalid(t *testing.T) {
+	dir := writePlugin(t, map[string]string{"images/icon@2x.png": pngOf(t, 72, 72)})
+	if err := os.Remove(filepath.Join(dir, "plugin.exe")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Archive(io.Discard, dir)
+	want := manifest.Problems{
+		{Path: "$.CodePathWin", Message: "file not found: plugin.exe"},
+		{Path: "$.Icon", Message: "images/icon@2x.png must be 144x144 but is 72x72"},
+	}
+	if diff := cmp.Diff(want, err); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestArchiveFile(t *testing.T) {
+	dir := writePlugin(t, nil)
+	out := filepath.Join(t.TempDir(), "dist")
+
+	path, err := ArchiveFile(dir, out, WithExclude("pi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(out, "com.example.plugin.streamDeckPlugin") {
+		t.Errorf("unexpected path: %s", path)
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	for _, f := range zr.File {
+		if f.Name == "com.example.plugin.sdPlugin/pi/" {
+			t.Errorf("excluded directory is archived")
+		}
+	}
+}
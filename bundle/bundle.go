@@ -0,0 +1,359 @@
+// Package bundle assembles the .sdPlugin directory of a Go plugin.
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+// Target is the platform to compile the plugin for.
+type Target struct {
+	GOOS   string
+	GOARCH string
+}
+
+func (t Target) String() string {
+	return t.GOOS + "/" + t.GOARCH
+}
+
+// ParseTarget parses a target like darwin/arm64.
+func ParseTarget(s string) (Target, error) {
+	goos, goarch, ok := strings.Cut(s, "/")
+	if !ok || goos == "" || goarch == "" {
+		return Target{}, fmt.Errorf("invalid target: %q: must be GOOS/GOARCH", s)
+	}
+	return Target{goos, goarch}, nil
+}
+
+// DefaultTargets is the platforms supported by the Stream Deck application.
+// The darwin binaries are merged into a universal binary.
+var DefaultTargets = []Target{
+	{"darwin", "amd64"},
+	{"darwin", "arm64"},
+	{"windows", "amd64"},
+}
+
+// Config is the configuration of Build.
+type Config struct {
+	// Package is the Go package of the plugin. The default is ".".
+	Package string
+	// Assets is the directory containing manifest.json and the files
+	// referred from it, e.g. icons and property inspectors.
+	Assets string
+	// Output is the .sdPlugin directory to create. It is removed first if it
+	// exists. An output without the extension must be an empty directory if it
+	// exists, and the output must not contain Assets nor be inside Assets.
+	Output string
+	// Binary is the name of the executable. The default is CodePath in the
+	// manifest without .exe.
+	Binary string
+	// Targets is the platforms to compile for. The default is DefaultTargets
+	// of the platforms in OS of the manifest.
+	Targets []Target
+	// Stderr receives the output of go build. The default is os.Stderr.
+	Stderr io.Writer
+
+	// goBuild compiles the package into the file at out. It is replaced in tests.
+	goBuild func(ctx context.Context, target Target, pkg, out string, stderr io.Writer) error
+}
+
+// Build cross-compiles the plugin, copies the assets, writes CodePathMac
+// and CodePathWin into manifest.json, and validates the result.
+// The error is manifest.Problems if the assembled plugin is invalid.
+func Build(ctx context.Context, cfg Config) (*manifest.Manifest, error) {
+	if cfg.Package == "" {
+		cfg.Package = "."
+	}
+	if cfg.Stderr == nil {
+		cfg.Stderr = os.Stderr
+	}
+	if cfg.goBuild == nil {
+		cfg.goBuild = goBuild
+	}
+
+	m, err := manifest.Load(filepath.Join(cfg.Assets, "manifest.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Targets) == 0 {
+		cfg.Targets = defaultTargets(m)
+	}
+	if cfg.Binary == "" {
+		cfg.Binary = strings.TrimSuffix(filepath.Base(m.CodePath), ".exe")
+	}
+	if cfg.Binary == "" || cfg.Binary == "." {
+		return nil, fmt.Errorf("binary name is required")
+	}
+
+	err = prepareOutput(cfg.Assets, cfg.Output)
+	if err != nil {
+		return nil, err
+	}
+	err = copyAssets(cfg.Assets, cfg.Output)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "streamdeck-pack")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	if len(cfg.Targets) == 0 {
+		return nil, fmt.Errorf("no target to build")
+	}
+
+	var darwin [][]byte
+	m.CodePathMac = nil
+	m.CodePathWin = nil
+	for _, t := range cfg.Targets {
+		out := filepath.Join(tmp, cfg.Binary+"-"+t.GOOS+"-"+t.GOARCH)
+		switch t.GOOS {
+		case "darwin":
+			err = cfg.goBuild(ctx, t, cfg.Package, out, cfg.Stderr)
+			if err != nil {
+				return nil, err
+			}
+			bin, err := os.ReadFile(out)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read binary: %w", err)
+			}
+			darwin = append(darwin, bin)
+		case "windows":
+			if m.CodePathWin != nil {
+				return nil, fmt.Errorf("multiple windows targets are not supported: %s", t)
+			}
+			win := cfg.Binary + ".exe"
+			err = cfg.goBuild(ctx, t, cfg.Package, filepath.Join(cfg.Output, win), cfg.Stderr)
+			if err != nil {
+				return nil, err
+			}
+			m.CodePathWin = manifest.OptionalString(win)
+		default:
+			return nil, fmt.Errorf("unsupported target: %s", t)
+		}
+	}
+	if len(darwin) > 0 {
+		err = writeDarwinBinary(filepath.Join(cfg.Output, cfg.Binary), darwin)
+		if err != nil {
+			return nil, err
+		}
+		m.CodePathMac = manifest.OptionalString(cfg.Binary)
+	}
+
+	if m.CodePathMac != nil {
+		m.CodePath = *m.CodePathMac
+	} else {
+		m.CodePath = *m.CodePathWin
+	}
+
+	err = writeManifest(filepath.Join(cfg.Output, "manifest.json"), m)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.ValidateDir(cfg.Output).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func defaultTargets(m *manifest.Manifest) []Target {
+	var ts []Target
+	for _, t := range DefaultTargets {
+		for _, o := range m.OS {
+			if (o.Platform == manifest.PlatformMac && t.GOOS == "darwin") ||
+				(o.Platform == manifest.PlatformWindows && t.GOOS == "windows") {
+				ts = append(ts, t)
+				break
+			}
+		}
+	}
+	return ts
+}
+
+func goBuild(ctx context.Context, target Target, pkg, out string, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, "go", "build", "-trimpath", "-o", out, pkg)
+	cmd.Env = append(os.Environ(), "GOOS="+target.GOOS, "GOARCH="+target.GOARCH, "CGO_ENABLED=0")
+	cmd.Stdout = stderr
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("failed to build for %s: %w", target, err)
+	}
+	return nil
+}
+
+func writeDarwinBinary(path string, binaries [][]byte) error {
+	var buf bytes.Buffer
+	if len(binaries) == 1 {
+		buf.Write(binaries[0])
+	} else {
+		err := WriteFat(&buf, binaries...)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := os.WriteFile(path, buf.Bytes(), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to write binary: %w", err)
+	}
+	return nil
+}
+
+// prepareOutput empties the output directory. An existing output is removed
+// only if it is a .sdPlugin directory or an empty directory, and the output
+// must not contain the assets nor be inside them not to remove the sources.
+func prepareOutput(assets, output string) error {
+	if output == "" {
+		return fmt.Errorf("output directory is required")
+	}
+	realAssets, err := realPath(assets)
+	if err != nil {
+		return err
+	}
+	realOutput, err := realPath(output)
+	if err != nil {
+		return err
+	}
+	if within(realOutput, realAssets) {
+		return fmt.Errorf("output directory must not contain assets: %s", output)
+	}
+	if within(realAssets, realOutput) {
+		return fmt.Errorf("output directory must not be inside assets: %s", output)
+	}
+
+	info, err := os.Stat(output)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return fmt.Errorf("failed to clean output directory: %w", err)
+	case !info.IsDir():
+		return fmt.Errorf("output is not a directory: %s", output)
+	case strings.HasSuffix(filepath.Base(realOutput), PluginDirExtension):
+		err = os.RemoveAll(output)
+		if err != nil {
+			return fmt.Errorf("failed to clean output directory: %w", err)
+		}
+	default:
+		entries, err := os.ReadDir(output)
+		if err != nil {
+			return fmt.Errorf("failed to clean output directory: %w", err)
+		}
+		if len(entries) > 0 {
+			return fmt.Errorf("output directory is not empty and not %s: %s", PluginDirExtension, output)
+		}
+	}
+
+	err = os.MkdirAll(output, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	return nil
+}
+
+// realPath returns the absolute path with symbolic links resolved as far as
+// the path exists.
+func realPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if real, err := filepath.EvalSymlinks(abs); err == nil {
+		return real, nil
+	}
+	dir, err := realPath(filepath.Dir(abs))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.Base(abs)), nil
+}
+
+// within reports whether path is dir or inside dir.
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// copyAssets copies the files in src except manifest.json and hidden files.
+func copyAssets(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rel == "manifest.json" {
+			return nil
+		}
+
+		out := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(out, 0o755)
+		}
+		return copyFile(path, out)
+	})
+}
+
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return out.Close()
+}
+
+func writeManifest(path string, m *manifest.Manifest) error {
+	bs, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	err = os.WriteFile(path, append(bs, '\n'), 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
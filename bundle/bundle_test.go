@@ -0,0 +1,216 @@
+package bundle
+
+import (
+	"context"
+	"debug/macho"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const testManifest = `{
+	"Actions": [{"Icon": "images/action", "Name": "Action", "States": [{"Image": "images/action"}], "UUID": "com.example.plugin.action"}],
+	"Author": "author",
+	"CodePath": "plugin",
+	"Description": "description",
+	"Icon": "images/icon",
+	"Name": "Plugin",
+	"Version": "1.0.0",
+	"SDKVersion": 2,
+	"OS": [{"Platform": "mac", "MinimumVersion": "10.15"}, {"Platform": "windows", "MinimumVersion": "10"}],
+	"Software": {"MinimumVersion": "5.0"}
+}`
+
+func fakeGoBuild(built *[]Target) func(ctx context.Context, target Target, pkg, out string, stderr io.Writer) error {
+	return func(ctx context.Context, target Target, pkg, out string, stderr io.Writer) error {
+		*built = append(*built, target)
+		data := []byte("MZ")
+		if target.GOOS == "darwin" {
+			cpu := macho.CpuAmd64
+			if target.GOARCH == "arm64" {
+				cpu = macho.CpuArm64
+			}
+			data = thinMachO(cpu, pkg)
+		}
+		return os.WriteFile(out, data, 0o755)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	assets := t.TempDir()
+	writeFiles(t, assets, map[string]string{
+		"manifest.json":     testManifest,
+		"images/icon.png":   "icon",
+		"images/action.svg": "<svg/>",
+		".DS_Store":         "",
+	})
+	output := filepath.Join(t.TempDir(), "com.example.plugin.sdPlugin")
+
+	var built []Target
+	m, err := Build(context.Background(), Config{
+		Package: "./cmd/plugin",
+		Assets:  assets,
+		Output:  output,
+		goBuild: fakeGoBuild(&built),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(DefaultTargets, built); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if m.CodePath != "plugin" || *m.CodePathMac != "plugin" || *m.CodePathWin != "plugin.exe" {
+		t.Errorf("unexpected code paths: %s, %s, %s", m.CodePath, *m.CodePathMac, *m.CodePathWin)
+	}
+
+	loaded, err := manifest.Load(filepath.Join(output, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m, loaded, cmpopts.IgnoreUnexported(manifest.State{})); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	ff, err := macho.OpenFat(filepath.Join(output, "plugin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ff.Close()
+	if len(ff.Arches) != 2 {
+		t.Errorf("want universal binary but got %d arches", len(ff.Arches))
+	}
+
+	for _, name := range []string{"plugin.exe", "images/icon.png", "images/action.svg"} {
+		if _, err := os.Stat(filepath.Join(output, filepath.FromSlash(name))); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(output, ".DS_Store")); !os.IsNotExist(err) {
+		t.Errorf("hidden file is copied: %v", err)
+	}
+}
+
+func TestBuild_Invalid(t *testing.T) {
+	assets := t.TempDir()
+	writeFiles(t, assets, map[string]string{
+		"manifest.json":   testManifest,
+		"images/icon.png": "icon",
+	})
+
+	var built []Target
+	_, err := Build(context.Background(), Config{
+		Assets:  assets,
+		Output:  filepath.Join(t.TempDir(), "com.example.plugin.sdPlugin"),
+		Targets: []Target{{"darwin", "arm64"}},
+		goBuild: fakeGoBuild(&built),
+	})
+
+	want := manifest.Problems{
+		{Path: "$.Actions[0].Icon", Message: "image not found: images/action.png or images/action.svg"},
+		{Path: "$.Actions[0].States[0].Image", Message: "image not found: images/action.png or images/action.svg"},
+	}
+	if diff := cmp.Diff(want, err); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestBuild_DefaultTargets(t *testing.T) {
+	assets := t.TempDir()
+	writeFiles(t, assets, map[string]string{
+		"manifest.json":     strings.Replace(testManifest, `, {"Platform": "windows", "MinimumVersion": "10"}`, "", 1),
+		"images/icon.png":   "icon",
+		"images/action.png": "action",
+	})
+
+	var built []Target
+	m, err := Build(context.Background(), Config{
+		Assets:  assets,
+		Output:  filepath.Join(t.TempDir(), "com.example.plugin.sdPlugin"),
+		goBuild: fakeGoBuild(&built),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]Target{{"darwin", "amd64"}, {"darwin", "arm64"}}, built); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+	if m.CodePathWin != nil {
+		t.Errorf("unexpected CodePathWin: %s", *m.CodePathWin)
+	}
+}
+
+func TestBuild_Output(t *testing.T) {
+	for name, tt := range map[string]struct {
+		assets string
+		output string
+	}{
+		"same":             {"plugin/assets", "plugin/assets"},
+		"parent":           {"plugin/assets", "plugin"},
+		"ancestor":         {"plugin/assets", "."},
+		"inside":           {"plugin", "plugin/dist.sdPlugin"},
+		"not empty plugin": {"assets", "plugin"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				filepath.Join(tt.assets, "manifest.json"): testManifest,
+				"plugin/main.go": "package main",
+			})
+			wd, err := os.Getwd()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = os.Chdir(wd) })
+
+			_, err = Build(context.Background(), Config{Assets: tt.assets, Output: tt.output, goBuild: fakeGoBuild(new([]Target))})
+			if err == nil || !strings.Contains(err.Error(), "output") {
+				t.Errorf("want error on output but got %v", err)
+			}
+			for _, f := range []string{filepath.Join(tt.assets, "manifest.json"), "plugin/main.go"} {
+				if _, err := os.Stat(f); err != nil {
+					t.Errorf("sources are removed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	got, err := ParseTarget("darwin/arm64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (Target{"darwin", "arm64"}) {
+		t.Errorf("unexpected target: %v", got)
+	}
+
+	for _, s := range []string{"darwin", "/arm64", "darwin/"} {
+		if _, err := ParseTarget(s); err == nil {
+			t.Errorf("want error for %q", s)
+		}
+	}
+}
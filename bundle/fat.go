@@ -0,0 +1,68 @@
+package bundle
+
+import (
+	"bytes"
+	"debug/macho"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const fatMagic = 0xcafebabe
+
+// WriteFat writes a universal (fat) Mach-O binary containing the thin
+// Mach-O binaries, so that a single executable runs on both Intel and
+// Apple silicon Macs without lipo.
+func WriteFat(w io.Writer, binaries ...[]byte) error {
+	if len(binaries) == 0 {
+		return fmt.Errorf("no binary for the fat binary")
+	}
+
+	type arch struct {
+		cpu    macho.Cpu
+		subCpu uint32
+		align  uint32
+		offset uint32
+		data   []byte
+	}
+	archs := make([]arch, len(binaries))
+	seen := make(map[macho.Cpu]bool)
+	// fat_header and fat_arch are 8 and 20 bytes.
+	offset := uint32(8 + 20*len(binaries))
+	for i, b := range binaries {
+		f, err := macho.NewFile(bytes.NewReader(b))
+		if err != nil {
+			return fmt.Errorf("invalid Mach-O binary at %d: %w", i, err)
+		}
+		if seen[f.Cpu] {
+			return fmt.Errorf("duplicated architecture: %s", f.Cpu)
+		}
+		seen[f.Cpu] = true
+
+		align := uint32(12)
+		if f.Cpu == macho.CpuArm64 {
+			align = 14
+		}
+		offset = alignUp(offset, align)
+		archs[i] = arch{f.Cpu, f.SubCpu, align, offset, b}
+		offset += uint32(len(b))
+	}
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.BigEndian, [2]uint32{fatMagic, uint32(len(archs))})
+	for _, a := range archs {
+		_ = binary.Write(&buf, binary.BigEndian, [5]uint32{uint32(a.cpu), a.subCpu, a.offset, uint32(len(a.data)), a.align})
+	}
+	for _, a := range archs {
+		buf.Write(make([]byte, int(a.offset)-buf.Len()))
+		buf.Write(a.data)
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func alignUp(n, align uint32) uint32 {
+	mask := uint32(1)<<align - 1
+	return (n + mask) &^ mask
+}
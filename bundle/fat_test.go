@@ -0,0 +1,70 @@
+package bundle
+
+import (
+	"bytes"
+	"debug/macho"
+	"encoding/binary"
+	"testing"
+)
+
+// thinMachO returns a minimal 64-bit Mach-O executable for the cpu.
+func thinMachO(cpu macho.Cpu, body string) []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, [8]uint32{macho.Magic64, uint32(cpu), 3, uint32(macho.TypeExec), 0, 0, 0, 0})
+	buf.WriteString(body)
+	return buf.Bytes()
+}
+
+func TestWriteFat(t *testing.T) {
+	amd64 := thinMachO(macho.CpuAmd64, "amd64")
+	arm64 := thinMachO(macho.CpuArm64, "arm64")
+
+	var buf bytes.Buffer
+	err := WriteFat(&buf, amd64, arm64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ff, err := macho.NewFatFile(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ff.Arches) != 2 {
+		t.Fatalf("want 2 arches but got %d", len(ff.Arches))
+	}
+	for i, want := range []struct {
+		cpu   macho.Cpu
+		align uint32
+		data  []byte
+	}{
+		{macho.CpuAmd64, 12, amd64},
+		{macho.CpuArm64, 14, arm64},
+	} {
+		a := ff.Arches[i]
+		if a.Cpu != want.cpu || a.Align != want.align {
+			t.Errorf("arch %d: want %s align %d but got %s align %d", i, want.cpu, want.align, a.Cpu, a.Align)
+		}
+		if a.Offset%(1<<a.Align) != 0 {
+			t.Errorf("arch %d: offset %d is not aligned", i, a.Offset)
+		}
+		got := buf.Bytes()[a.Offset : a.Offset+a.Size]
+		if !bytes.Equal(got, want.data) {
+			t.Errorf("arch %d: unexpected data: %q", i, got)
+		}
+	}
+}
+
+func TestWriteFat_Invalid(t *testing.T) {
+	for name, binaries := range map[string][][]byte{
+		"empty":      nil,
+		"not mach-o": {[]byte("MZ")},
+		"duplicated": {thinMachO(macho.CpuArm64, ""), thinMachO(macho.CpuArm64, "")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := WriteFat(&bytes.Buffer{}, binaries...)
+			if err == nil {
+				t.Error("want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package bundle
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/morikuni/go-stream-deck-sdk/internal/fsutil"
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+// The sizes of the images at @1x required by the Stream Deck application.
+// The @2x images are twice as large.
+var (
+	PluginIconSize        = image.Pt(72, 72)
+	CategoryIconSize      = image.Pt(28, 28)
+	ActionIconSize        = image.Pt(20, 20)
+	KeyImageSize          = image.Pt(72, 72)
+	EncoderIconSize       = image.Pt(72, 72)
+	EncoderBackgroundSize = image.Pt(200, 100)
+)
+
+// Icon is an image referred from the manifest.
+type Icon struct {
+	// Path is the JSON path in the manifest.
+	Path string
+	// Image is the path without the extension in the plugin directory.
+	Image string
+	// Size is the size at @1x.
+	Size image.Point
+}
+
+// Icons returns the images referred from the manifest with the required sizes.
+func Icons(m *manifest.Manifest) []Icon {
+	icons := []Icon{{"$.Icon", m.Icon, PluginIconSize}}
+	if m.CategoryIcon != nil {
+		icons = append(icons, Icon{"$.CategoryIcon", *m.CategoryIcon, CategoryIconSize})
+	}
+	for i, a := range m.Actions {
+		path := fmt.Sprintf("$.Actions[%d]", i)
+		icons = append(icons, Icon{path + ".Icon", a.Icon, ActionIconSize})
+		for j, s := range a.States {
+			spath := fmt.Sprintf("%s.States[%d]", path, j)
+			icons = append(icons, Icon{spath + ".Image", s.Image, KeyImageSize})
+			if s.MultiActionImage != nil {
+				icons = append(icons, Icon{spath + ".MultiActionImage", *s.MultiActionImage, KeyImageSize})
+			}
+		}
+		if a.Encoder != nil && a.Encoder.Icon != nil {
+			icons = append(icons, Icon{path + ".Encoder.Icon", *a.Encoder.Icon, EncoderIconSize})
+		}
+		if a.Encoder != nil && a.Encoder.Background != nil {
+			icons = append(icons, Icon{path + ".Encoder.background", *a.Encoder.Background, EncoderBackgroundSize})
+		}
+	}
+	return icons
+}
+
+// CheckIcons checks that the PNG and GIF images referred from the manifest
+// have the required size, and their @2x versions exist.
+// SVG images are not checked because they are scalable.
+func CheckIcons(dir string, m *manifest.Manifest) manifest.Problems {
+	var ps manifest.Problems
+	add := func(path, format string, a ...interface{}) {
+		ps = append(ps, &manifest.Problem{Path: path, Message: fmt.Sprintf(format, a...)})
+	}
+
+	for _, icon := range Icons(m) {
+		if icon.Image == "" || fsutil.IsFile(filepath.Join(dir, filepath.FromSlash(icon.Image+".svg"))) {
+			continue
+		}
+		for _, ext := range []string{".png", ".gif"} {
+			file := icon.Image + ext
+			size, err := imageSize(filepath.Join(dir, filepath.FromSlash(file)))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				add(icon.Path, "invalid image %s: %v", file, err)
+				break
+			}
+			if size != icon.Size {
+				add(icon.Path, "%s must be %dx%d but is %dx%d", file, icon.Size.X, icon.Size.Y, size.X, size.Y)
+			}
+
+			file2x := icon.Image + "@2x" + ext
+			size, err = imageSize(filepath.Join(dir, filepath.FromSlash(file2x)))
+			if os.IsNotExist(err) {
+				add(icon.Path, "%s is missing", file2x)
+				break
+			}
+			if err != nil {
+				add(icon.Path, "invalid image %s: %v", file2x, err)
+				break
+			}
+			if size != icon.Size.Mul(2) {
+				add(icon.Path, "%s must be %dx%d but is %dx%d", file2x, icon.Size.X*2, icon.Size.Y*2, size.X, size.Y)
+			}
+			break
+		}
+	}
+
+	return ps
+}
+
+func imageSize(path string) (image.Point, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Point{}, err
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return image.Point{}, err
+	}
+	return image.Pt(cfg.Width, cfg.Height), nil
+}
@@ -0,0 +1,45 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+func TestCheckIcons(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"icon.png":       pngOf(t, 72, 72),
+		"icon@2x.png":    pngOf(t, 144, 144),
+		"category.png":   pngOf(t, 28, 28),
+		"action.png":     pngOf(t, 40, 40),
+		"action@2x.png":  pngOf(t, 40, 40),
+		"state.svg":      "<svg/>",
+		"multi.png":      "not png",
+		"background.png": pngOf(t, 200, 100),
+	})
+
+	m := &manifest.Manifest{
+		Icon:         "icon",
+		CategoryIcon: manifest.OptionalString("category"),
+		Actions: []manifest.Action{
+			{
+				Icon:    "action",
+				States:  []manifest.State{{Image: "state", MultiActionImage: manifest.OptionalString("multi")}},
+				Encoder: &manifest.Encoder{Background: manifest.OptionalString("background")},
+			},
+		},
+	}
+
+	got := CheckIcons(dir, m)
+	want := manifest.Problems{
+		{Path: "$.CategoryIcon", Message: "category@2x.png is missing"},
+		{Path: "$.Actions[0].Icon", Message: "action.png must be 20x20 but is 40x40"},
+		{Path: "$.Actions[0].States[0].MultiActionImage", Message: "invalid image multi.png: image: unknown format"},
+		{Path: "$.Actions[0].Encoder.background", Message: "background@2x.png is missing"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,216 @@
+package bundle
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/morikuni/go-stream-deck-sdk/internal/fsutil"
+	"github.com/morikuni/go-stream-deck-sdk/internal/imageutil"
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+	"golang.org/x/image/draw"
+)
+
+// IconSource is the source of icons rendered at each required size.
+type IconSource interface {
+	// Render renders the source fitting in the size with the aspect ratio kept.
+	Render(size image.Point) (image.Image, error)
+}
+
+// LoadIconSource reads an SVG file or an image file decodable by the image
+// package, e.g. PNG.
+func LoadIconSource(path string) (IconSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read icon source: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".svg") {
+		return NewSVGIconSource(data)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode icon source: %w", err)
+	}
+	return NewImageIconSource(img), nil
+}
+
+// NewImageIconSource returns an IconSource scaling the image.
+// The image should be at least as large as the @2x sizes.
+func NewImageIconSource(img image.Image) IconSource {
+	return imageIconSource{img}
+}
+
+type imageIconSource struct {
+	img image.Image
+}
+
+func (s imageIconSource) Render(size image.Point) (image.Image, error) {
+	dst := image.NewNRGBA(image.Rectangle{Max: size})
+	draw.CatmullRom.Scale(dst, imageutil.FitRect(s.img.Bounds().Size(), size), s.img, s.img.Bounds(), draw.Over, nil)
+	return dst, nil
+}
+
+// NewSVGIconSource returns an IconSource rasterizing the SVG at each size.
+func NewSVGIconSource(svg []byte) (IconSource, error) {
+	// Parse once to report errors early.
+	_, err := oksvg.ReadIconStream(bytes.NewReader(svg), oksvg.WarnErrorMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SVG: %w", err)
+	}
+	return svgIconSource{svg}, nil
+}
+
+type svgIconSource struct {
+	svg []byte
+}
+
+func (s svgIconSource) Render(size image.Point) (image.Image, error) {
+	icon, err := oksvg.ReadIconStream(bytes.NewReader(s.svg), oksvg.WarnErrorMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SVG: %w", err)
+	}
+
+	r := imageutil.FitRect(image.Pt(int(icon.ViewBox.W), int(icon.ViewBox.H)), size)
+	icon.SetTarget(float64(r.Min.X), float64(r.Min.Y), float64(r.Dx()), float64(r.Dy()))
+
+	dst := image.NewRGBA(image.Rectangle{Max: size})
+	scanner := rasterx.NewScannerGV(size.X, size.Y, dst, dst.Bounds())
+	icon.Draw(rasterx.NewDasher(size.X, size.Y, scanner), 1)
+	return dst, nil
+}
+
+// WriteIcon writes name.png and name@2x.png of the size at @1x into dir.
+// name is the image path without the extension as in the manifest.
+func WriteIcon(dir, name string, src IconSource, size image.Point) error {
+	for _, v := range []struct {
+		suffix string
+		size   image.Point
+	}{
+		{"", size},
+		{"@2x", size.Mul(2)},
+	} {
+		img, err := src.Render(v.size)
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		err = png.Encode(&buf, img)
+		if err != nil {
+			return fmt.Errorf("failed to encode icon: %w", err)
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(name+v.suffix+".png"))
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			return fmt.Errorf("failed to create icon directory: %w", err)
+		}
+		err = os.WriteFile(path, buf.Bytes(), 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to write icon: %w", err)
+		}
+	}
+	return nil
+}
+
+type IconOption iconOption
+
+// WithIconSource uses the source for the image instead of the default one.
+// image is the path without the extension as in the manifest.
+func WithIconSource(image string, src IconSource) IconOption {
+	return func(c *iconConfig) {
+		c.sources[image] = src
+	}
+}
+
+type iconOption func(*iconConfig)
+
+type iconConfig struct {
+	sources map[string]IconSource
+}
+
+// GenerateIcons writes the @1x and @2x PNG images of every image referred
+// from the manifest into the plugin directory, rendered from src at the
+// required sizes. Then it verifies that the references resolve.
+// It returns the written images without the extension.
+// The error is manifest.Problems if an image is referred with different
+// sizes or the manifest is invalid with the generated images.
+func GenerateIcons(dir string, m *manifest.Manifest, src IconSource, opts ...IconOption) ([]string, error) {
+	cfg := iconConfig{sources: make(map[string]IconSource)}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	var ps manifest.Problems
+	first := make(map[string]Icon)
+	for _, icon := range Icons(m) {
+		if icon.Image == "" {
+			continue
+		}
+		if f, ok := first[icon.Image]; ok {
+			if f.Size != icon.Size {
+				ps = append(ps, &manifest.Problem{
+					Path:    icon.Path,
+					Message: fmt.Sprintf("%s is %dx%d but is also used as %dx%d at %s", icon.Image, icon.Size.X, icon.Size.Y, f.Size.X, f.Size.Y, f.Path),
+				})
+			}
+			continue
+		}
+		first[icon.Image] = icon
+	}
+	if err := ps.Err(); err != nil {
+		return nil, err
+	}
+
+	images := make([]string, 0, len(first))
+	for image := range first {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	for _, image := range images {
+		s, ok := cfg.sources[image]
+		if !ok {
+			s = src
+		}
+		err := WriteIcon(dir, image, s, first[image].Size)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", image, err)
+		}
+	}
+
+	err := VerifyIcons(dir, m).Err()
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// VerifyIcons checks that every image referred from the manifest exists
+// in the plugin directory in addition to CheckIcons.
+func VerifyIcons(dir string, m *manifest.Manifest) manifest.Problems {
+	var ps manifest.Problems
+	for _, icon := range Icons(m) {
+		if icon.Image != "" && !resolves(dir, icon.Image) {
+			ps = append(ps, &manifest.Problem{Path: icon.Path, Message: fmt.Sprintf("image not found: %s", icon.Image)})
+		}
+	}
+	return append(ps, CheckIcons(dir, m)...)
+}
+
+func resolves(dir, image string) bool {
+	for _, f := range manifest.ImageFiles(image) {
+		if fsutil.IsFile(filepath.Join(dir, filepath.FromSlash(f))) {
+			return true
+		}
+	}
+	return false
+}
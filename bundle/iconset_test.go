@@ -0,0 +1,168 @@
+package bundle
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+const testSVG = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 20 10"><rect x="0" y="0" width="20" height="10" fill="#ff0000"/></svg>`
+
+func TestSVGIconSource(t *testing.T) {
+	src, err := NewSVGIconSource([]byte(testSVG))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := src.Render(image.Pt(40, 40))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 40, 40) {
+		t.Fatalf("unexpected bounds: %v", img.Bounds())
+	}
+	// The 2:1 image is fit in the middle.
+	for _, c := range []struct {
+		p    image.Point
+		want color.NRGBA
+	}{
+		{image.Pt(20, 20), color.NRGBA{R: 0xff, A: 0xff}},
+		{image.Pt(20, 5), color.NRGBA{}},
+	} {
+		got := color.NRGBAModel.Convert(img.At(c.p.X, c.p.Y)).(color.NRGBA)
+		if got != c.want {
+			t.Errorf("%v: want %v but got %v", c.p, c.want, got)
+		}
+	}
+}
+
+func TestGenerateIcons(t *testing.T) {
+	dir := t.TempDir()
+	m := &manifest.Manifest{
+		Icon:         "images/plugin",
+		CategoryIcon: manifest.OptionalString("images/category"),
+		Actions: []manifest.Action{
+			{Icon: "images/action", States: []manifest.State{{Image: "images/key"}, {Image: "images/key"}}},
+		},
+	}
+	keySource := NewImageIconSource(image.NewRGBA(image.Rect(0, 0, 10, 10)))
+
+	images, err := GenerateIcons(dir, m, NewImageIconSource(image.NewRGBA(image.Rect(0, 0, 300, 300))), WithIconSource("images/key", keySource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"images/action", "images/category", "images/key", "images/plugin"}, images); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+
+	for name, want := range map[string]image.Point{
+		"images/plugin.png":      PluginIconSize,
+		"images/plugin@2x.png":   PluginIconSize.Mul(2),
+		"images/category.png":    CategoryIconSize,
+		"images/category@2x.png": CategoryIconSize.Mul(2),
+		"images/action.png":      ActionIconSize,
+		"images/action@2x.png":   ActionIconSize.Mul(2),
+		"images/key.png":         KeyImageSize,
+		"images/key@2x.png":      KeyImageSize.Mul(2),
+	} {
+		got, err := imageSize(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s: want %v but got %v", name, want, got)
+		}
+	}
+}
+
+func TestGenerateIcons_SizeConflict(t *testing.T) {
+	m := &manifest.Manifest{
+		Icon: "icon",
+		Actions: []manifest.Action{
+			{Icon: "icon", States: []manifest.State{{Image: "icon"}}},
+		},
+	}
+
+	_, err := GenerateIcons(t.TempDir(), m, NewImageIconSource(image.NewRGBA(image.Rect(0, 0, 1, 1))))
+	var ps manifest.Problems
+	if !errors.As(err, &ps) {
+		t.Fatalf("want Problems but got %v", err)
+	}
+	want := manifest.Problems{
+		{Path: "$.Actions[0].Icon", Message: "icon is 20x20 but is also used as 72x72 at $.Icon"},
+	}
+	if diff := cmp.Diff(want, ps); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestGenerateIcons_BuilderDefaults(t *testing.T) {
+	m, err := manifest.New("Plugin").
+		Author("author").
+		Icon("images/plugin").
+		Binary("plugin").
+		Action("com.example.plugin.action", func(a *manifest.ActionBuilder) { a.Name("Action") }).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	_, err = GenerateIcons(dir, m, NewImageIconSource(image.NewRGBA(image.Rect(0, 0, 1, 1))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ps := CheckIcons(dir, m); ps != nil {
+		t.Errorf("invalid icons: %v", ps)
+	}
+}
+
+func TestVerifyIcons(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"icon.svg":      "<svg/>",
+		"action.png":    pngOf(t, 20, 20),
+		"action@2x.png": pngOf(t, 40, 40),
+	})
+	m := &manifest.Manifest{
+		Icon: "icon",
+		Actions: []manifest.Action{
+			{Icon: "action", States: []manifest.State{{Image: "key"}}},
+		},
+	}
+
+	got := VerifyIcons(dir, m)
+	want := manifest.Problems{
+		{Path: "$.Actions[0].States[0].Image", Message: "image not found: key"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestLoadIconSource(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"icon.svg": testSVG,
+		"icon.png": pngOf(t, 10, 10),
+		"icon.txt": "text",
+	})
+
+	for _, name := range []string{"icon.svg", "icon.png"} {
+		if _, err := LoadIconSource(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if _, err := LoadIconSource(filepath.Join(dir, "icon.txt")); err == nil {
+		t.Error("want error")
+	}
+	if _, err := LoadIconSource(filepath.Join(dir, "missing.png")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("want ErrNotExist but got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package streamdeck
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// fakeClock fires the timers created by afterFunc when the time is advanced.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Duration
+	slept  time.Duration
+	timers []*fakeTimer
+}
+
+// fakeEpoch is the wall clock time of a fakeClock at the start.
+var fakeEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Now returns the wall clock time.
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return fakeEpoch.Add(c.now + c.slept)
+}
+
+// sleep advances the wall clock without firing the timers as the system sleeps.
+func (c *fakeClock) sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.slept += d
+}
+
+type fakeTimer struct {
+	clock   *fakeClock
+	at      time.Duration
+	f       func()
+	stopped bool
+}
+
+func (c *fakeClock) afterFunc(d time.Duration, f func()) timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, at: c.now + d, f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := !t.stopped
+	t.stopped = true
+	return active
+}
+
+// advance fires the timers due in d in order, including the ones created by the fired timers.
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now + d
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].at < c.timers[j].at })
+		var next *fakeTimer
+		for len(c.timers) > 0 && next == nil {
+			t := c.timers[0]
+			if t.at > end {
+				break
+			}
+			c.timers = c.timers[1:]
+			if !t.stopped {
+				t.stopped = true
+				next = t
+			}
+		}
+		if next == nil {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+		c.now = next.at
+		c.mu.Unlock()
+
+		next.f()
+	}
+}
@@ -0,0 +1,96 @@
+// Command streamdeck-icons generates the @1x and @2x PNG images of every
+// image referred from manifest.json from a single source image or SVG.
+//
+//	streamdeck-icons -src icon.svg com.example.plugin.sdPlugin
+//
+// With -check, it only verifies the images referred from manifest.json.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/morikuni/go-stream-deck-sdk/bundle"
+	"github.com/morikuni/go-stream-deck-sdk/internal/cliutil"
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "streamdeck-icons:", err)
+		os.Exit(1)
+	}
+}
+
+type sourceFlags map[string]string
+
+func (f sourceFlags) String() string {
+	return fmt.Sprint(map[string]string(f))
+}
+
+func (f sourceFlags) Set(s string) error {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == '=' {
+			f[s[:i]] = s[i+1:]
+			return nil
+		}
+	}
+	return fmt.Errorf("must be image=path: %q", s)
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("streamdeck-icons", flag.ContinueOnError)
+	src := fs.String("src", "", "source image or SVG of the icons")
+	check := fs.Bool("check", false, "only verify the images referred from manifest.json")
+	sources := sourceFlags{}
+	fs.Var(sources, "image", "source for an image in manifest.json as image=path, e.g. images/key=key.svg (repeatable)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: streamdeck-icons [flags] plugin-dir")
+		fs.PrintDefaults()
+	}
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || (!*check && *src == "") {
+		fs.Usage()
+		return errors.New("plugin directory and -src are required")
+	}
+	dir := fs.Arg(0)
+
+	m, err := manifest.Load(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		return err
+	}
+
+	if *check {
+		return cliutil.Describe("invalid icons", bundle.VerifyIcons(dir, m).Err())
+	}
+
+	s, err := bundle.LoadIconSource(*src)
+	if err != nil {
+		return err
+	}
+	var opts []bundle.IconOption
+	for image, path := range sources {
+		s, err := bundle.LoadIconSource(path)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, bundle.WithIconSource(image, s))
+	}
+
+	images, err := bundle.GenerateIcons(dir, m, s, opts...)
+	if err != nil {
+		return cliutil.Describe("invalid icons", err)
+	}
+	for _, image := range images {
+		fmt.Println(filepath.Join(dir, filepath.FromSlash(image)) + ".png")
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+// Command streamdeck-new creates a new plugin project using the SDK.
+//
+//	streamdeck-new -uuid com.example.counter -actions counter,reset [dir]
+//
+// The project contains main.go, the actions with their manifest entries,
+// a manifest generator, a property inspector stub, icons, a Makefile and
+// a test using streamdecktest. The commands of the SDK used in the Makefile
+// are kept in go.mod by tools.go. dir defaults to the last part of the UUID.
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/morikuni/go-stream-deck-sdk/bundle"
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+	"github.com/morikuni/go-stream-deck-sdk/propertyinspector"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9-]+)+$`)
+	actionIDPattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "streamdeck-new:", err)
+		os.Exit(1)
+	}
+}
+
+type project struct {
+	UUID              string
+	Name              string
+	Author            string
+	Description       string
+	Module            string
+	Binary            string
+	Icon              string
+	PropertyInspector string
+	Replace           string
+	Actions           []*action
+}
+
+type action struct {
+	ID      string
+	Type    string
+	Name    string
+	Tooltip string
+	UUID    string
+	Icon    string
+	Image   string
+}
+
+// ManifestAction implements manifest.ActionSource in the same way as the
+// generated actions package.
+func (a *action) ManifestAction() manifest.Action {
+	return manifest.Action{
+		Icon:    a.Icon,
+		Name:    a.Name,
+		States:  []manifest.State{{Image: a.Image}},
+		Tooltip: manifest.OptionalString(a.Tooltip),
+		UUID:    a.UUID,
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("streamdeck-new", flag.ContinueOnError)
+	uuid := fs.String("uuid", "", "reverse-DNS UUID of the plugin, e.g. com.example.counter")
+	name := fs.String("name", "", "name of the plugin (default derived from the UUID)")
+	author := fs.String("author", os.Getenv("USER"), "author of the plugin")
+	description := fs.String("description", "", "description of the plugin (default the name)")
+	module := fs.String("module", "", "Go module path (default the last part of the UUID)")
+	actions := fs.String("actions", "action", "comma separated IDs of the actions, e.g. counter,reset")
+	icon := fs.String("icon", "", "source image or SVG of the icons (default a generated SVG)")
+	replace := fs.String("replace", "", "local path of the SDK to replace the module with")
+	tidy := fs.Bool("tidy", true, "run go mod tidy in the project")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: streamdeck-new -uuid uuid [flags] [dir]")
+		fs.PrintDefaults()
+	}
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if !uuidPattern.MatchString(*uuid) {
+		fs.Usage()
+		return fmt.Errorf("-uuid must be a reverse-DNS format with lowercase alphanumeric characters, hyphens and periods: %q", *uuid)
+	}
+
+	last := (*uuid)[strings.LastIndex(*uuid, ".")+1:]
+	p := &project{
+		UUID:              *uuid,
+		Name:              *name,
+		Author:            *author,
+		Description:       *description,
+		Module:            *module,
+		Binary:            last,
+		Icon:              "images/plugin",
+		PropertyInspector: "propertyinspector/index.html",
+	}
+	if p.Name == "" {
+		p.Name = title(last)
+	}
+	if p.Author == "" {
+		p.Author = "unknown"
+	}
+	if p.Description == "" {
+		p.Description = p.Name
+	}
+	if p.Module == "" {
+		p.Module = last
+	}
+	if *replace != "" {
+		p.Replace, err = filepath.Abs(*replace)
+		if err != nil {
+			return err
+		}
+	}
+	for _, id := range strings.Split(*actions, ",") {
+		id = strings.TrimSpace(id)
+		if !actionIDPattern.MatchString(id) {
+			return fmt.Errorf("action ID must be lowercase alphanumeric characters and hyphens: %q", id)
+		}
+		name := title(id)
+		p.Actions = append(p.Actions, &action{
+			ID:      id,
+			Type:    strings.ReplaceAll(name, " ", ""),
+			Name:    name,
+			Tooltip: name,
+			UUID:    *uuid + "." + id,
+			Icon:    "images/actions/" + id,
+			Image:   "images/actions/" + id + "-key",
+		})
+	}
+
+	dir := fs.Arg(0)
+	if dir == "" {
+		dir = last
+	}
+	err = create(dir, p, *icon)
+	if err != nil {
+		return err
+	}
+
+	if *tidy {
+		cmd := exec.Command("go", "mod", "tidy")
+		cmd.Dir = dir
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+		err = cmd.Run()
+		if err != nil {
+			return fmt.Errorf("failed to run go mod tidy: %w", err)
+		}
+	}
+
+	fmt.Println(dir)
+	return nil
+}
+
+func create(dir string, p *project, iconSource string) error {
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) > 0 {
+		return fmt.Errorf("directory is not empty: %s", dir)
+	}
+
+	pluginDir := filepath.Join(dir, p.UUID+".sdPlugin")
+	files := []struct {
+		template string
+		path     string
+	}{
+		{"go.mod.tmpl", "go.mod"},
+		{"main.go.tmpl", "main.go"},
+		{"tools.go.tmpl", "tools.go"},
+		{"actions.go.tmpl", "actions/actions.go"},
+		{"actions_test.go.tmpl", "actions/actions_test.go"},
+		{"gen-manifest.go.tmpl", "gen-manifest/main.go"},
+		{"Makefile.tmpl", "Makefile"},
+		{"icon.svg.tmpl", "icon.svg"},
+		{"gitignore.tmpl", ".gitignore"},
+	}
+	for _, f := range files {
+		err := writeTemplate(filepath.Join(dir, filepath.FromSlash(f.path)), f.template, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The manifest is the same as the one generated by gen-manifest,
+	// which is used to generate the icons and validated by the Makefile later.
+	sources := make([]manifest.ActionSource, len(p.Actions))
+	for i, a := range p.Actions {
+		sources[i] = a
+	}
+	m, err := manifest.New(p.Name).
+		Author(p.Author).
+		Description(p.Description).
+		Icon(p.Icon).
+		Binary(p.Binary).
+		PropertyInspector(p.PropertyInspector).
+		Actions(sources...).
+		Build()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(pluginDir, 0o755)
+	if err != nil {
+		return err
+	}
+	bs, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(pluginDir, "manifest.json"), append(bs, '\n'), 0o644)
+	if err != nil {
+		return err
+	}
+
+	err = writePropertyInspector(filepath.Join(pluginDir, filepath.FromSlash(p.PropertyInspector)), p.Name)
+	if err != nil {
+		return err
+	}
+
+	if iconSource == "" {
+		iconSource = filepath.Join(dir, "icon.svg")
+	}
+	src, err := bundle.LoadIconSource(iconSource)
+	if err != nil {
+		return err
+	}
+	_, err = bundle.GenerateIcons(pluginDir, m, src)
+	return err
+}
+
+func writeTemplate(path, name string, data interface{}) error {
+	tmpl, err := template.New(name).Delims("[[", "]]").ParseFS(templates, "templates/"+name)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		return fmt.Errorf("failed to execute %s: %w", name, err)
+	}
+	out := buf.Bytes()
+	if strings.HasSuffix(path, ".go") {
+		out, err = format.Source(out)
+		if err != nil {
+			return fmt.Errorf("failed to format %s: %w", path, err)
+		}
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0o644)
+}
+
+func writePropertyInspector(path, title string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	// The stub has no fields. Replace struct{}{} with the settings type of
+	// an action, or edit the HTML by hand.
+	err = propertyinspector.Generate(&buf, struct{}{}, propertyinspector.WithTitle(title))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// title converts an ID like volume-up into Volume Up.
+func title(id string) string {
+	words := strings.FieldsFunc(id, func(r rune) bool { return r == '-' || r == '_' })
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
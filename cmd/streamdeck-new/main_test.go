@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/morikuni/go-stream-deck-sdk/bundle"
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+func TestRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "counter")
+	err := run([]string{"-uuid", "com.example.counter", "-author", "author", "-actions", "counter,volume-up", "-tidy=false", dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range []string{"go.mod", "main.go", "tools.go", "actions/actions.go", "actions/actions_test.go", "gen-manifest/main.go", "Makefile", "icon.svg", ".gitignore"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(f))); err != nil {
+			t.Error(err)
+		}
+	}
+
+	pluginDir := filepath.Join(dir, "com.example.counter.sdPlugin")
+	m, err := manifest.Load(filepath.Join(pluginDir, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the binary is built by make pack, so only the manifest itself is checked.
+	if ps := m.Validate(); ps != nil {
+		t.Errorf("invalid manifest: %v", ps)
+	}
+	if ps := bundle.VerifyIcons(pluginDir, m); ps != nil {
+		t.Errorf("invalid icons: %v", ps)
+	}
+	if len(m.Actions) != 2 || m.Actions[1].UUID != "com.example.counter.volume-up" || m.Actions[1].Name != "Volume Up" {
+		t.Errorf("unexpected actions: %+v", m.Actions)
+	}
+
+	err = run([]string{"-uuid", "com.example.counter", "-tidy=false", dir})
+	if err == nil {
+		t.Error("want error for non-empty directory")
+	}
+}
+
+func TestRun_Invalid(t *testing.T) {
+	for name, args := range map[string][]string{
+		"uuid":   {"-uuid", "Counter"},
+		"action": {"-uuid", "com.example.counter", "-actions", "Counter"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			args = append(args, "-tidy=false", filepath.Join(t.TempDir(), "counter"))
+			if err := run(args); err == nil {
+				t.Error("want error")
+			}
+		})
+	}
+}
+
+// TestRun_Build builds and tests the generated project with this SDK.
+// It uses the module cache only.
+func TestRun_Build(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the generated project")
+	}
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOSUMDB", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+
+	dir := filepath.Join(t.TempDir(), "counter")
+	err := run([]string{"-uuid", "com.example.counter", "-author", "author", "-actions", "counter,volume-up", "-replace", "../..", dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"vet", "./..."},
+		{"test", "./..."},
+	} {
+		cmd := exec.Command("go", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("go %v: %v\n%s", args, err, out)
+		}
+	}
+
+	cmd := exec.Command("go", "run", "./gen-manifest")
+	cmd.Dir = dir
+	got, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join(dir, "com.example.counter.sdPlugin", "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("gen-manifest differs from the generated manifest:\n%s\n%s", got, want)
+	}
+}
@@ -0,0 +1,89 @@
+// Command streamdeck-pack cross-compiles a Go plugin and assembles the
+// .sdPlugin directory.
+//
+//	streamdeck-pack -assets assets -o com.example.plugin.sdPlugin ./cmd/plugin
+//
+// The darwin binaries are merged into a universal binary, and CodePathMac and
+// CodePathWin in manifest.json are written from the built binaries.
+// With -archive, the distributable .streamDeckPlugin archive is written as well.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/morikuni/go-stream-deck-sdk/bundle"
+	"github.com/morikuni/go-stream-deck-sdk/internal/cliutil"
+)
+
+func main() {
+	err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "streamdeck-pack:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("streamdeck-pack", flag.ContinueOnError)
+	assets := fs.String("assets", ".", "directory containing manifest.json and the assets")
+	output := fs.String("o", "", "the .sdPlugin directory to create")
+	binary := fs.String("binary", "", "name of the executable (default CodePath in manifest.json)")
+	archive := fs.String("archive", "", "directory to write the .streamDeckPlugin archive of the plugin")
+	targets := fs.String("targets", "", "comma separated GOOS/GOARCH to build (default "+joinTargets(bundle.DefaultTargets)+" of the platforms in manifest.json)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: streamdeck-pack [flags] [package]")
+		fs.PrintDefaults()
+	}
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		fs.Usage()
+		return errors.New("-o is required")
+	}
+
+	cfg := bundle.Config{
+		Package: fs.Arg(0),
+		Assets:  *assets,
+		Output:  *output,
+		Binary:  *binary,
+	}
+	if *targets != "" {
+		for _, s := range strings.Split(*targets, ",") {
+			t, err := bundle.ParseTarget(strings.TrimSpace(s))
+			if err != nil {
+				return err
+			}
+			cfg.Targets = append(cfg.Targets, t)
+		}
+	}
+
+	_, err = bundle.Build(context.Background(), cfg)
+	if err != nil {
+		return cliutil.Describe("invalid plugin", err)
+	}
+
+	if *archive != "" {
+		path, err := bundle.ArchiveFile(*output, *archive)
+		if err != nil {
+			return cliutil.Describe("invalid plugin", err)
+		}
+		fmt.Println(path)
+	}
+	return nil
+}
+
+func joinTargets(ts []bundle.Target) string {
+	ss := make([]string, len(ts))
+	for i, t := range ts {
+		ss[i] = t.String()
+	}
+	return strings.Join(ss, ",")
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+const (
+	examplePackage = "../../example/helloworld"
+	examplePlugin  = "com.github.morikuni.goStreamDeckSDK"
+)
+
+var exampleAssets = filepath.Join(examplePackage, examplePlugin+".sdPlugin")
+
+// offline makes go build use the module cache only.
+func offline(t *testing.T) {
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOSUMDB", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+}
+
+func TestRun(t *testing.T) {
+	offline(t)
+	dir := t.TempDir()
+	out := filepath.Join(dir, examplePlugin+".sdPlugin")
+	dist := filepath.Join(dir, "dist")
+
+	err := run([]string{"-assets", exampleAssets, "-o", out, "-archive", dist, "-targets", "darwin/arm64", examplePackage})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := manifest.Load(filepath.Join(out, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.CodePathMac == nil {
+		t.Fatalf("CodePathMac must be written: %+v", m)
+	}
+	if _, err := os.Stat(filepath.Join(out, *m.CodePathMac)); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dist, examplePlugin+".streamDeckPlugin")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRun_Invalid(t *testing.T) {
+	offline(t)
+
+	// the manifest refers to the images which are not copied.
+	assets := t.TempDir()
+	bs, err := os.ReadFile(filepath.Join(exampleAssets, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(assets, "manifest.json"), bs, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), examplePlugin+".sdPlugin")
+
+	for name, tt := range map[string]struct {
+		args []string
+
+		want string
+	}{
+		"no output": {
+			[]string{"-assets", exampleAssets, examplePackage},
+			"-o is required",
+		},
+		"target": {
+			[]string{"-assets", exampleAssets, "-o", out, "-targets", "plan9", examplePackage},
+			"plan9",
+		},
+		"plugin": {
+			[]string{"-assets", assets, "-o", out, "-targets", "darwin/arm64", examplePackage},
+			"invalid plugin:\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := run(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("want error containing %q but got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+
+	"github.com/morikuni/go-stream-deck-sdk/internal/protocol"
 )
 
 // Command interface is to restrict command object that can
@@ -21,6 +23,10 @@ var _ = []Command{
 	(*SetImage)(nil),
 	(*ShowAlert)(nil),
 	(*ShowOK)(nil),
+	(*SetSettings)(nil),
+	(*GetSettings)(nil),
+	(*SetGlobalSettings)(nil),
+	(*GetGlobalSettings)(nil),
 }
 
 type noPayloadCommand struct{}
@@ -33,13 +39,7 @@ func (*payloadCommand) commandMark() {}
 
 func (*payloadCommand) hasPayload() {}
 
-type commandPayload struct {
-	Event   string          `json:"event"`
-	Context string          `json:"context,omitempty"`
-	Action  string          `json:"action,omitempty"`
-	Device  string          `json:"device,omitempty"`
-	Payload json.RawMessage `json:"payload,omitempty"`
-}
+type commandPayload = protocol.CommandPayload
 
 func newCommandPayload(cmd Command, pluginUUID string) (*commandPayload, error) {
 	p := &commandPayload{
@@ -151,6 +151,66 @@ func (cmd *ShowOK) getContext() string {
 	return string(cmd.Context)
 }
 
+type SetSettings struct {
+	payloadCommand
+
+	Context InstanceID `json:"-"`
+
+	// Settings is the value marshaled into the settings object.
+	Settings interface{} `json:"-"`
+}
+
+func (*SetSettings) event() string {
+	return "setSettings"
+}
+
+func (cmd *SetSettings) getContext() string {
+	return string(cmd.Context)
+}
+
+// MarshalJSON marshals the settings as the payload.
+func (cmd *SetSettings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cmd.Settings)
+}
+
+type GetSettings struct {
+	noPayloadCommand
+
+	Context InstanceID `json:"-"`
+}
+
+func (*GetSettings) event() string {
+	return "getSettings"
+}
+
+func (cmd *GetSettings) getContext() string {
+	return string(cmd.Context)
+}
+
+type SetGlobalSettings struct {
+	payloadCommand
+
+	// Settings is the value marshaled into the settings object.
+	Settings interface{} `json:"-"`
+}
+
+func (*SetGlobalSettings) event() string {
+	return "setGlobalSettings"
+}
+
+// MarshalJSON marshals the settings as the payload.
+func (cmd *SetGlobalSettings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cmd.Settings)
+}
+
+type GetGlobalSettings struct {
+	noPayloadCommand
+}
+
+func (*GetGlobalSettings) event() string {
+	return "getGlobalSettings"
+}
+
 type Target int
 
 const (
@@ -165,3 +225,27 @@ type Image string
 func NewImage(filetype string, data []byte) Image {
 	return Image(fmt.Sprintf("data:image/%s;base64,%s", filetype, base64.StdEncoding.EncodeToString(data)))
 }
+
+// isHighPriority reports whether the command must be sent before other
+// commands under the rate limit.
+func isHighPriority(cmd Command) bool {
+	switch cmd.(type) {
+	case *ShowAlert, *ShowOK, *LogMessage:
+		return true
+	default:
+		return false
+	}
+}
+
+// coalesceKeyOf returns the key to coalesce the pending commands under the
+// rate limit. It returns an empty string if the command must not be coalesced.
+func coalesceKeyOf(cmd Command) string {
+	switch cmd := cmd.(type) {
+	case *SetTitle:
+		return fmt.Sprintf("%s/%s/%d/%d", cmd.event(), cmd.Context, cmd.Target, cmd.State)
+	case *SetImage:
+		return fmt.Sprintf("%s/%s/%d/%d", cmd.event(), cmd.Context, cmd.Target, cmd.State)
+	default:
+		return ""
+	}
+}
@@ -98,6 +98,51 @@ func TestNewCommandPayload(t *testing.T) {
 				Context: "instanceID",
 			},
 		},
+		{
+			cmd: &SetSettings{
+				Context: "instanceID",
+				Settings: map[string]interface{}{
+					"key": "value",
+				},
+			},
+			want: &commandPayload{
+				Event:   "setSettings",
+				Context: "instanceID",
+				Payload: toJSON(map[string]string{
+					"key": "value",
+				}),
+			},
+		},
+		{
+			cmd: &GetSettings{
+				Context: "instanceID",
+			},
+			want: &commandPayload{
+				Event:   "getSettings",
+				Context: "instanceID",
+			},
+		},
+		{
+			cmd: &SetGlobalSettings{
+				Settings: map[string]interface{}{
+					"key": "value",
+				},
+			},
+			want: &commandPayload{
+				Event:   "setGlobalSettings",
+				Context: "pluginUUID",
+				Payload: toJSON(map[string]string{
+					"key": "value",
+				}),
+			},
+		},
+		{
+			cmd: &GetGlobalSettings{},
+			want: &commandPayload{
+				Event:   "getGlobalSettings",
+				Context: "pluginUUID",
+			},
+		},
 	} {
 		t.Run(fmt.Sprintf("%T", tt.cmd), func(t *testing.T) {
 			cp, err := newCommandPayload(tt.cmd, "pluginUUID")
@@ -1,9 +1,11 @@
 package streamdeck
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"sync"
 
 	"golang.org/x/net/websocket"
 )
@@ -11,6 +13,19 @@ import (
 type Conn struct {
 	conn       *websocket.Conn
 	pluginUUID string
+	info       json.RawMessage
+
+	// fields for the rate limit. queue is nil if the rate limit is disabled.
+	queue       *sendQueue
+	limiter     *rateLimiter
+	onSendError func(error)
+	closing     chan struct{}
+	sent        chan struct{}
+
+	// sendFailure is called with a command failed to be sent under
+	// the rate limit, because the sender does not see the error.
+	sendFailureMu sync.Mutex
+	sendFailure   func(payload *commandPayload)
 }
 
 type DialOption dialOption
@@ -33,12 +48,46 @@ func WithRegisterEvent(event string) DialOption {
 	}
 }
 
+// WithRegistrationInfo sets the JSON of the registration info,
+// which is given by -info flag by default.
+func WithRegistrationInfo(info string) DialOption {
+	return func(config *dialConfig) {
+		config.info = info
+	}
+}
+
+// WithRateLimit limits the number of commands sent per second.
+// Send returns without waiting for the command to be sent, and the pending
+// SetTitle and SetImage for the same key are coalesced so that only the latest
+// one is sent. ShowAlert, ShowOK and LogMessage are sent before other commands
+// and never coalesced.
+func WithRateLimit(perSecond, burst int) DialOption {
+	return func(config *dialConfig) {
+		config.rateLimit = perSecond
+		config.burst = burst
+	}
+}
+
+// WithSendErrorHandler sets the function called with an error on sending
+// a command asynchronously under the rate limit.
+// A logMessage failed to be sent is written to the file logger of the SDK
+// using the connection regardless of the handler.
+func WithSendErrorHandler(f func(error)) DialOption {
+	return func(config *dialConfig) {
+		config.onSendError = f
+	}
+}
+
 type dialOption func(*dialConfig)
 
 type dialConfig struct {
 	port          string
 	pluginUUID    string
 	registerEvent string
+	info          string
+	rateLimit     int
+	burst         int
+	onSendError   func(error)
 }
 
 func Dial(opts ...DialOption) (*Conn, error) {
@@ -53,8 +102,7 @@ func Dial(opts ...DialOption) (*Conn, error) {
 		port := fs.String("port", "", "port to bind websocket server")
 		uuid := fs.String("pluginUUID", "", "the ID of the plugin")
 		event := fs.String("registerEvent", "", "the event type to register websocket connection")
-		// define info flag to avoid error.
-		_ = fs.String("info", "", "the event type to register websocket connection")
+		info := fs.String("info", "", "the information of the application, the plugin and the devices")
 
 		err := fs.Parse(os.Args[1:])
 		if err != nil {
@@ -70,6 +118,9 @@ func Dial(opts ...DialOption) (*Conn, error) {
 		if cfg.registerEvent == "" {
 			cfg.registerEvent = *event
 		}
+		if cfg.info == "" {
+			cfg.info = *info
+		}
 	}
 
 	conn, err := websocket.Dial("ws://localhost:"+cfg.port, "", "http://localhost:"+cfg.port)
@@ -85,10 +136,44 @@ func Dial(opts ...DialOption) (*Conn, error) {
 		return nil, fmt.Errorf("error during registratino procedure: %w", err)
 	}
 
-	return &Conn{
-		conn,
-		cfg.pluginUUID,
-	}, nil
+	c := &Conn{
+		conn:       conn,
+		pluginUUID: cfg.pluginUUID,
+	}
+	if cfg.info != "" {
+		c.info = json.RawMessage(cfg.info)
+	}
+	if cfg.rateLimit > 0 {
+		c.queue = newSendQueue()
+		c.limiter = newRateLimiter(cfg.rateLimit, cfg.burst)
+		c.onSendError = cfg.onSendError
+		c.closing = make(chan struct{})
+		c.sent = make(chan struct{})
+		go c.sendLoop()
+	}
+
+	return c, nil
+}
+
+// Info decodes the registration info. It returns nil if the info is not given.
+// The info is decoded lazily so that an unexpected info does not prevent
+// the plugin from connecting.
+func (c *Conn) Info() (*RegistrationInfo, error) {
+	if c.info == nil {
+		return nil, nil
+	}
+
+	var info RegistrationInfo
+	err := json.Unmarshal(c.info, &info)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registration info: %w", err)
+	}
+	return &info, nil
+}
+
+// RawInfo returns the JSON of the registration info, or nil if it is not given.
+func (c *Conn) RawInfo() json.RawMessage {
+	return c.info
 }
 
 func (c *Conn) Receive() (Event, error) {
@@ -112,6 +197,13 @@ func (c *Conn) Send(cmd Command) error {
 		return err
 	}
 
+	if c.queue != nil {
+		if !c.queue.push(payload, isHighPriority(cmd), coalesceKeyOf(cmd)) {
+			return fmt.Errorf("failed to send a command: connection is closed: %v", cmd)
+		}
+		return nil
+	}
+
 	err = websocket.JSON.Send(c.conn, payload)
 	if err != nil {
 		return fmt.Errorf("failed to send a command: %w: %v", err, cmd)
@@ -120,6 +212,57 @@ func (c *Conn) Send(cmd Command) error {
 	return nil
 }
 
+func (c *Conn) sendLoop() {
+	defer close(c.sent)
+
+	for c.queue.wait() {
+		c.limiter.wait(c.closing)
+
+		payload, ok := c.queue.pop()
+		if !ok {
+			continue
+		}
+
+		err := websocket.JSON.Send(c.conn, payload)
+		if err == nil {
+			continue
+		}
+		c.sendFailed(payload)
+		if c.onSendError != nil {
+			c.onSendError(fmt.Errorf("failed to send a command: %w: %s", err, payload.Event))
+		}
+	}
+}
+
+func (c *Conn) setSendFailure(f func(payload *commandPayload)) {
+	c.sendFailureMu.Lock()
+	defer c.sendFailureMu.Unlock()
+
+	c.sendFailure = f
+}
+
+func (c *Conn) sendFailed(payload *commandPayload) {
+	c.sendFailureMu.Lock()
+	f := c.sendFailure
+	c.sendFailureMu.Unlock()
+
+	if f != nil {
+		f(payload)
+	}
+}
+
+// Close closes the connection. The pending commands under the rate limit
+// are sent before closing.
 func (c *Conn) Close() error {
+	if c.queue != nil {
+		c.queue.close()
+		select {
+		case <-c.closing:
+		default:
+			close(c.closing)
+		}
+		<-c.sent
+	}
+
 	return c.conn.Close()
 }
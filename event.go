@@ -3,6 +3,8 @@ package streamdeck
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/morikuni/go-stream-deck-sdk/internal/protocol"
 )
 
 // Event interface is to restrict event object that can
@@ -35,26 +37,7 @@ type eventMarkImpl struct{}
 func (*eventMarkImpl) eventMark() {}
 
 type eventPayload struct {
-	Event   string          `json:"event"`
-	Payload json.RawMessage `json:"payload"`
-	Raw     json.RawMessage `json:"-"`
-}
-
-func (ep *eventPayload) UnmarshalJSON(bs []byte) error {
-	var extractor struct {
-		Event   string          `json:"event"`
-		Payload json.RawMessage `json:"payload"`
-	}
-
-	err := json.Unmarshal(bs, &extractor)
-	if err != nil {
-		return err
-	}
-
-	ep.Event = extractor.Event
-	ep.Payload = extractor.Payload
-	ep.Raw = bs
-	return nil
+	protocol.EventPayload
 }
 
 func (ep eventPayload) Typed() (Event, error) {
@@ -98,16 +81,9 @@ func (ep eventPayload) Typed() (Event, error) {
 		return nil, fmt.Errorf("unknown event: %s", ep.Event)
 	}
 
-	if len(ep.Payload) > 0 {
-		err := json.Unmarshal(ep.Payload, e)
-		if err != nil {
-			return nil, fmt.Errorf("failed to bind event payload to %T: %w", e, err)
-		}
-	}
-
-	err := json.Unmarshal(ep.Raw, e)
+	err := ep.Bind(e)
 	if err != nil {
-		return nil, fmt.Errorf("failed to bind event to %T: %w", e, err)
+		return nil, err
 	}
 
 	return e, nil
@@ -271,6 +247,8 @@ const (
 	DeviceTypeStreamDeckMobile DeviceType = 3
 	DeviceTypeCorsairGKeys     DeviceType = 4
 	DeviceTypeStreamDeckPanel  DeviceType = 5
+	DeviceTypeCorsairVoyager   DeviceType = 6
+	DeviceTypeStreamDeckPlus   DeviceType = 7
 )
 
 type Size struct {
@@ -308,3 +286,72 @@ type InstanceID string
 type DeviceID string
 
 type ApplicationID string
+
+func instanceIDOf(ev Event) (InstanceID, bool) {
+	switch ev := ev.(type) {
+	case *DidReceiveSettings:
+		return ev.Context, true
+	case *KeyDown:
+		return ev.Context, true
+	case *KeyUp:
+		return ev.Context, true
+	case *WillAppear:
+		return ev.Context, true
+	case *WillDisappear:
+		return ev.Context, true
+	case *TitleParametersDidChange:
+		return ev.Context, true
+	case *PropertyInspectorDidAppear:
+		return ev.Context, true
+	case *PropertyInspectorDidDisappear:
+		return ev.Context, true
+	case *SendToPlugin:
+		return ev.Context, true
+	default:
+		return "", false
+	}
+}
+
+func actionIDOf(ev Event) (ActionID, bool) {
+	switch ev := ev.(type) {
+	case *DidReceiveSettings:
+		return ev.Action, true
+	case *KeyDown:
+		return ev.Action, true
+	case *KeyUp:
+		return ev.Action, true
+	case *WillAppear:
+		return ev.Action, true
+	case *WillDisappear:
+		return ev.Action, true
+	case *TitleParametersDidChange:
+		return ev.Action, true
+	case *PropertyInspectorDidAppear:
+		return ev.Action, true
+	case *PropertyInspectorDidDisappear:
+		return ev.Action, true
+	case *SendToPlugin:
+		return ev.Action, true
+	default:
+		return "", false
+	}
+}
+
+func settingsOf(ev Event) (json.RawMessage, bool) {
+	switch ev := ev.(type) {
+	case *DidReceiveSettings:
+		return ev.Settings, true
+	case *KeyDown:
+		return ev.Settings, true
+	case *KeyUp:
+		return ev.Settings, true
+	case *WillAppear:
+		return ev.Settings, true
+	case *WillDisappear:
+		return ev.Settings, true
+	case *TitleParametersDidChange:
+		return ev.Settings, true
+	default:
+		return nil, false
+	}
+}
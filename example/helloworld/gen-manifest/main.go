@@ -8,61 +8,26 @@ import (
 )
 
 func main() {
+	m, err := manifest.New("Hello World").
+		Author("morikuni").
+		Description("hello world app").
+		Version("0.0.0").
+		Icon("images/plugin").
+		Binary("helloworld").
+		OS(manifest.PlatformMac, "10").
+		Action("com.github.morikuni.helloworld", func(a *manifest.ActionBuilder) {
+			a.Name("Hello World").
+				Icon("images/action").
+				State("images/key", nil)
+		}).
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "\t")
-	err := enc.Encode(&manifest.Manifest{
-		Actions: []manifest.Action{
-			{
-				Icon:                  "icon",
-				Name:                  "Hello World",
-				PropertyInspectorPath: nil,
-				States: []manifest.State{
-					{
-						Image:            "icon",
-						MultiActionImage: nil,
-						Name:             nil,
-						Title:            nil,
-						ShowTitle:        nil,
-						TitleColor:       nil,
-						TitleAlignment:   nil,
-						FontFamily:       nil,
-						FontStyle:        nil,
-						FontSize:         nil,
-						FontUnderline:    nil,
-					},
-				},
-				SupportedInMultiActions: nil,
-				Tooltip:                 nil,
-				UUID:                    "com.github.morikuni.helloworld",
-				VisibleInActionsList:    nil,
-			},
-		},
-		Author:                "morikuni",
-		Category:              nil,
-		CategoryIcon:          nil,
-		CodePath:              "helloworld",
-		CodePathMac:           nil,
-		CodePathWin:           nil,
-		Description:           "hello world app",
-		Icon:                  "icon",
-		Name:                  "Hello World",
-		Profiles:              nil,
-		PropertyInspectorPath: nil,
-		DefaultWindowSize:     nil,
-		URL:                   nil,
-		Version:               "0.0.0",
-		SDKVersion:            2,
-		OS: []manifest.OS{
-			{
-				Platform:       manifest.PlatformMac,
-				MinimumVersion: "10",
-			},
-		},
-		Software: manifest.Software{
-			MinimumVersion: "5.0",
-		},
-		ApplicationsToMonitor: nil,
-	})
+	err = enc.Encode(m)
 	if err != nil {
 		panic(err)
 	}
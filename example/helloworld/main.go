@@ -9,14 +9,21 @@ import (
 )
 
 func main() {
-	conn, err := streamdeck.Dial()
+	// Logs are written to the file until the connection is established.
+	logger, err := streamdeck.NewPluginFileLogger()
 	if err != nil {
-		// This log will be discarded because logs should be sent to the stream deck as a command.
 		panic(err)
 	}
+	defer logger.Close()
+
+	conn, err := streamdeck.Dial()
+	if err != nil {
+		logger.Log("failed to dial:", err)
+		return
+	}
 	defer conn.Close()
 
-	sdk := streamdeck.NewSDK(conn)
+	sdk := streamdeck.NewSDK(conn, streamdeck.WithFileLogger(logger), streamdeck.WithPanicRecovery())
 	sdk.Log("start")
 	defer func() {
 		sdk.Log("exit", recover())
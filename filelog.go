@@ -0,0 +1,201 @@
+package streamdeck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLogMaxSize    = 1 << 20
+	defaultLogMaxBackups = 3
+)
+
+// FileLogger writes logs to a local file with size-based rotation.
+// It is useful for logs that cannot be sent via Stream Deck API, e.g.
+// logs before the connection is established or after it is closed.
+type FileLogger struct {
+	mu sync.Mutex
+
+	path       string
+	maxSize    int64
+	maxBackups int
+	now        func() time.Time
+
+	file *os.File
+	size int64
+}
+
+type FileLoggerOption fileLoggerOption
+
+// WithMaxSize sets the size in bytes at which the log file is rotated.
+func WithMaxSize(size int64) FileLoggerOption {
+	return func(l *FileLogger) {
+		l.maxSize = size
+	}
+}
+
+// WithMaxBackups sets the number of rotated files to retain.
+func WithMaxBackups(n int) FileLoggerOption {
+	return func(l *FileLogger) {
+		l.maxBackups = n
+	}
+}
+
+type fileLoggerOption func(*FileLogger)
+
+// NewFileLogger opens the file at path to append logs.
+// Rotated files are named path.1, path.2, ... from newest to oldest.
+func NewFileLogger(path string, opts ...FileLoggerOption) (*FileLogger, error) {
+	l := &FileLogger{
+		path:       path,
+		maxSize:    defaultLogMaxSize,
+		maxBackups: defaultLogMaxBackups,
+		now:        time.Now,
+	}
+	for _, o := range opts {
+		o(l)
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	err = l.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// NewPluginFileLogger opens logs/plugin.log in the directory of the
+// plugin executable, which is the .sdPlugin directory.
+func NewPluginFileLogger(opts ...FileLoggerOption) (*FileLogger, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find plugin directory: %w", err)
+	}
+
+	return NewFileLogger(filepath.Join(filepath.Dir(exe), "logs", "plugin.log"), opts...)
+}
+
+// Log prints log to the file in the manner of fmt.Println.
+func (l *FileLogger) Log(a ...interface{}) {
+	s := fmt.Sprintln(a...)
+	l.writeLine(s[:len(s)-1])
+}
+
+// Logf prints formatted log to the file.
+func (l *FileLogger) Logf(format string, a ...interface{}) {
+	l.writeLine(fmt.Sprintf(format, a...))
+}
+
+func (l *FileLogger) writeLine(s string) {
+	_, _ = l.Write([]byte(l.now().Format(time.RFC3339Nano) + " " + s + "\n"))
+}
+
+// Write implements io.Writer. The file is rotated before writing p
+// if the size would exceed the max size. If the rotation fails, p is
+// appended to the current file and the rotation is retried on the next write.
+func (l *FileLogger) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if l.size > 0 && l.size+int64(len(p)) > l.maxSize {
+		err := l.rotate()
+		if err != nil && l.file == nil {
+			return 0, err
+		}
+		// keep writing to the current file if only the rotation failed,
+		// e.g. a backup is locked on Windows, not to lose the logs.
+	}
+
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+func (l *FileLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+func (l *FileLogger) open() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	l.file = f
+	l.size = info.Size()
+	return nil
+}
+
+// rotate renames the file to the first backup and opens a new file.
+// The file is reopened even if the rotation fails so that logging continues.
+func (l *FileLogger) rotate() error {
+	err := l.file.Close()
+	l.file = nil
+	if err != nil {
+		return l.reopen(fmt.Errorf("failed to close log file: %w", err))
+	}
+
+	if l.maxBackups <= 0 {
+		err = os.Remove(l.path)
+		if err != nil && !os.IsNotExist(err) {
+			return l.reopen(fmt.Errorf("failed to remove log file: %w", err))
+		}
+		return l.open()
+	}
+
+	_ = os.Remove(l.backupPath(l.maxBackups))
+	for i := l.maxBackups - 1; i >= 1; i-- {
+		err = os.Rename(l.backupPath(i), l.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return l.reopen(fmt.Errorf("failed to rotate log file: %w", err))
+		}
+	}
+	err = os.Rename(l.path, l.backupPath(1))
+	if err != nil && !os.IsNotExist(err) {
+		return l.reopen(fmt.Errorf("failed to rotate log file: %w", err))
+	}
+
+	return l.open()
+}
+
+// reopen opens the file again after a failed rotation and returns err,
+// or the error of opening if the file cannot be opened either.
+func (l *FileLogger) reopen(err error) error {
+	oerr := l.open()
+	if oerr != nil {
+		return oerr
+	}
+	return err
+}
+
+func (l *FileLogger) backupPath(n int) string {
+	return l.path + "." + strconv.Itoa(n)
+}
@@ -0,0 +1,73 @@
+package streamdeck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileLogger_Rotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "plugin.log")
+
+	l, err := NewFileLogger(path, WithMaxSize(30), WithMaxBackups(2))
+	noError(t, err)
+	l.now = func() time.Time { return time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	// Each line is 27 bytes, so every log is written to a new file.
+	for _, msg := range []string{"aaaaaa", "bbbbbb", "cccccc", "dddddd"} {
+		l.Log(msg)
+	}
+	noError(t, l.Close())
+
+	for file, want := range map[string]string{
+		path:        "2022-01-02T03:04:05Z dddddd\n",
+		path + ".1": "2022-01-02T03:04:05Z cccccc\n",
+		path + ".2": "2022-01-02T03:04:05Z bbbbbb\n",
+	} {
+		got, err := os.ReadFile(file)
+		noError(t, err)
+		equal(t, string(got), want)
+	}
+
+	_, err = os.Stat(path + ".3")
+	if !os.IsNotExist(err) {
+		t.Fatal("backups beyond the limit must be removed:", err)
+	}
+}
+
+func TestFileLogger_RotateFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plugin.log")
+
+	// a non-empty directory at the backup path makes the rotation fail.
+	noError(t, os.MkdirAll(filepath.Join(path+".1", "locked"), 0o755))
+
+	l, err := NewFileLogger(path, WithMaxSize(30), WithMaxBackups(1))
+	noError(t, err)
+	l.now = func() time.Time { return time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	for _, msg := range []string{"aaaaaa", "bbbbbb", "cccccc"} {
+		l.Log(msg)
+	}
+	noError(t, l.Close())
+
+	got, err := os.ReadFile(path)
+	noError(t, err)
+	equal(t, string(got), "2022-01-02T03:04:05Z aaaaaa\n2022-01-02T03:04:05Z bbbbbb\n2022-01-02T03:04:05Z cccccc\n")
+}
+
+func TestFileLogger_Append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plugin.log")
+
+	for _, msg := range []string{"first", "second"} {
+		l, err := NewFileLogger(path)
+		noError(t, err)
+		l.now = func() time.Time { return time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC) }
+		l.Logf("%s log", msg)
+		noError(t, l.Close())
+	}
+
+	got, err := os.ReadFile(path)
+	noError(t, err)
+	equal(t, string(got), "2022-01-02T03:04:05Z first log\n2022-01-02T03:04:05Z second log\n")
+}
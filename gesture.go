@@ -0,0 +1,327 @@
+package streamdeck
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+type GestureType int
+
+const (
+	GestureTap GestureType = iota
+	GestureDoubleTap
+	GestureLongPress
+	GestureHoldRepeat
+)
+
+func (t GestureType) String() string {
+	switch t {
+	case GestureTap:
+		return "Tap"
+	case GestureDoubleTap:
+		return "DoubleTap"
+	case GestureLongPress:
+		return "LongPress"
+	case GestureHoldRepeat:
+		return "HoldRepeat"
+	default:
+		return "Unknown"
+	}
+}
+
+// Gesture is a gesture detected from KeyDown and KeyUp.
+// The fields are copied from the KeyDown starting the gesture.
+type Gesture struct {
+	Type GestureType
+
+	Action  ActionID
+	Context InstanceID
+	Device  DeviceID
+
+	Settings        json.RawMessage
+	Coordinates     Coordinates
+	State           int
+	IsInMultiAction bool
+
+	// Repeat is the number of HoldRepeat starting from 1.
+	Repeat int
+}
+
+// GestureFunc is called with a detected gesture.
+type GestureFunc func(ctx context.Context, g *Gesture) error
+
+// GestureConfig configures thresholds and callbacks of GestureDetector.
+// A nil callback disables the gesture.
+type GestureConfig struct {
+	OnTap        GestureFunc
+	OnDoubleTap  GestureFunc
+	OnLongPress  GestureFunc
+	OnHoldRepeat GestureFunc
+
+	// LongPressThreshold is the duration to hold a key to be a long press. Default is 500ms.
+	LongPressThreshold time.Duration
+	// DoubleTapInterval is the duration to wait for the second tap. Default is 300ms.
+	// A tap is reported after the interval if OnDoubleTap is set.
+	DoubleTapInterval time.Duration
+	// HoldRepeatInterval is the interval of HoldRepeat after a long press. Default is 100ms.
+	HoldRepeatInterval time.Duration
+
+	// OnError is called with an error returned by a callback called asynchronously by a timer.
+	OnError func(error)
+}
+
+// GestureDetector turns KeyDown and KeyUp of each instance into gestures.
+// The callbacks for a tap or a double tap are called in the handler if possible,
+// and the others are called by a timer in another goroutine.
+type GestureDetector struct {
+	cfg       GestureConfig
+	afterFunc func(d time.Duration, f func()) timer
+
+	mu      sync.Mutex
+	presses map[InstanceID]*press
+}
+
+type press struct {
+	ctx     context.Context
+	gesture Gesture
+	// first is the pending tap if the press is the second one of a double tap.
+	first    *Gesture
+	released bool
+	long     bool
+	canceled bool
+	timer    timer
+	// tapTimer waits for the second tap after the first tap is released.
+	tapTimer timer
+}
+
+// timer is the part of *time.Timer used by the types running functions
+// after a delay, which is faked in tests.
+type timer interface {
+	Stop() bool
+}
+
+func afterFunc(d time.Duration, f func()) timer {
+	return time.AfterFunc(d, f)
+}
+
+func NewGestureDetector(cfg GestureConfig) *GestureDetector {
+	if cfg.LongPressThreshold <= 0 {
+		cfg.LongPressThreshold = 500 * time.Millisecond
+	}
+	if cfg.DoubleTapInterval <= 0 {
+		cfg.DoubleTapInterval = 300 * time.Millisecond
+	}
+	if cfg.HoldRepeatInterval <= 0 {
+		cfg.HoldRepeatInterval = 100 * time.Millisecond
+	}
+	return &GestureDetector{
+		cfg:       cfg,
+		afterFunc: afterFunc,
+		presses:   make(map[InstanceID]*press),
+	}
+}
+
+// Middleware detects gestures from the events. The events are passed to
+// the next handler as is.
+func (d *GestureDetector) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, ev Event) error {
+			err := d.Handle(ctx, ev)
+			if err != nil {
+				return err
+			}
+			return next.Handle(ctx, ev)
+		})
+	}
+}
+
+// Handle implements Handler.
+func (d *GestureDetector) Handle(ctx context.Context, ev Event) error {
+	switch ev := ev.(type) {
+	case *KeyDown:
+		return d.keyDown(ctx, ev)
+	case *KeyUp:
+		return d.keyUp(ctx, ev)
+	case *WillDisappear:
+		d.cancel(ev.Context)
+	}
+	return nil
+}
+
+func (d *GestureDetector) keyDown(ctx context.Context, ev *KeyDown) error {
+	g := Gesture{
+		Type:            GestureTap,
+		Action:          ev.Action,
+		Context:         ev.Context,
+		Device:          ev.Device,
+		Settings:        ev.Settings,
+		Coordinates:     ev.Coordinates,
+		State:           ev.State,
+		IsInMultiAction: ev.IsInMultiAction,
+	}
+
+	// A multi action sends KeyDown and KeyUp at once, so it is always a tap.
+	if ev.IsInMultiAction {
+		return d.call(ctx, d.cfg.OnTap, &g)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	p := &press{ctx: ctx, gesture: g}
+	switch prev := d.presses[ev.Context]; {
+	case prev == nil:
+	case prev.tapTimer != nil:
+		// the first tap is being reported if the timer has already fired.
+		if prev.tapTimer.Stop() {
+			p.first = &prev.gesture
+		}
+	default:
+		d.stop(prev)
+	}
+	d.presses[ev.Context] = p
+
+	if d.cfg.OnLongPress != nil || d.cfg.OnHoldRepeat != nil {
+		p.timer = d.afterFunc(d.cfg.LongPressThreshold, func() {
+			d.longPress(ev.Context, p)
+		})
+	}
+
+	return nil
+}
+
+func (d *GestureDetector) longPress(id InstanceID, p *press) {
+	d.mu.Lock()
+	if p.canceled || p.released {
+		d.mu.Unlock()
+		return
+	}
+	p.long = true
+	// the second press is not a double tap anymore, so the first one is a tap.
+	first := p.first
+	p.first = nil
+	if d.cfg.OnHoldRepeat != nil {
+		p.timer = d.afterFunc(d.cfg.HoldRepeatInterval, func() {
+			d.holdRepeat(id, p, 1)
+		})
+	}
+	d.mu.Unlock()
+
+	if first != nil {
+		d.callAsync(p.ctx, d.cfg.OnTap, first)
+	}
+	g := p.gesture
+	g.Type = GestureLongPress
+	d.callAsync(p.ctx, d.cfg.OnLongPress, &g)
+}
+
+func (d *GestureDetector) holdRepeat(id InstanceID, p *press, n int) {
+	d.mu.Lock()
+	if p.canceled || p.released {
+		d.mu.Unlock()
+		return
+	}
+	p.timer = d.afterFunc(d.cfg.HoldRepeatInterval, func() {
+		d.holdRepeat(id, p, n+1)
+	})
+	d.mu.Unlock()
+
+	g := p.gesture
+	g.Type = GestureHoldRepeat
+	g.Repeat = n
+	d.callAsync(p.ctx, d.cfg.OnHoldRepeat, &g)
+}
+
+func (d *GestureDetector) keyUp(ctx context.Context, ev *KeyUp) error {
+	if ev.IsInMultiAction {
+		return nil
+	}
+
+	d.mu.Lock()
+	p, ok := d.presses[ev.Context]
+	if !ok || p.released {
+		// the key was pressed before the instance appeared.
+		d.mu.Unlock()
+		return nil
+	}
+	p.released = true
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+
+	switch {
+	case p.long:
+		delete(d.presses, ev.Context)
+		d.mu.Unlock()
+		return nil
+	case p.first != nil:
+		delete(d.presses, ev.Context)
+		d.mu.Unlock()
+		g := p.gesture
+		g.Type = GestureDoubleTap
+		return d.call(ctx, d.cfg.OnDoubleTap, &g)
+	case d.cfg.OnDoubleTap != nil:
+		p.tapTimer = d.afterFunc(d.cfg.DoubleTapInterval, func() {
+			d.delayedTap(ev.Context, p)
+		})
+		d.mu.Unlock()
+		return nil
+	default:
+		delete(d.presses, ev.Context)
+		d.mu.Unlock()
+		g := p.gesture
+		return d.call(ctx, d.cfg.OnTap, &g)
+	}
+}
+
+func (d *GestureDetector) delayedTap(id InstanceID, p *press) {
+	d.mu.Lock()
+	if p.canceled {
+		d.mu.Unlock()
+		return
+	}
+	if d.presses[id] == p {
+		delete(d.presses, id)
+	}
+	d.mu.Unlock()
+
+	g := p.gesture
+	d.callAsync(p.ctx, d.cfg.OnTap, &g)
+}
+
+func (d *GestureDetector) cancel(id InstanceID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stop(d.presses[id])
+	delete(d.presses, id)
+}
+
+func (d *GestureDetector) stop(p *press) {
+	if p == nil {
+		return
+	}
+	p.canceled = true
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	if p.tapTimer != nil {
+		p.tapTimer.Stop()
+	}
+}
+
+func (d *GestureDetector) call(ctx context.Context, f GestureFunc, g *Gesture) error {
+	if f == nil {
+		return nil
+	}
+	return f(ctx, g)
+}
+
+func (d *GestureDetector) callAsync(ctx context.Context, f GestureFunc, g *Gesture) {
+	err := d.call(ctx, f, g)
+	if err != nil && d.cfg.OnError != nil {
+		d.cfg.OnError(err)
+	}
+}
@@ -0,0 +1,103 @@
+package streamdeck
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGestureDetector(t *testing.T) {
+	type step struct {
+		ev      Event
+		advance time.Duration
+	}
+	down := &KeyDown{Context: "context"}
+	up := &KeyUp{Context: "context"}
+
+	for name, tt := range map[string]struct {
+		doubleTap bool
+		steps     []step
+
+		want []GestureType
+	}{
+		"tap": {
+			false,
+			[]step{{down, 0}, {up, 0}},
+			[]GestureType{GestureTap},
+		},
+		"delayed tap": {
+			true,
+			[]step{{down, 0}, {up, 100 * time.Millisecond}},
+			[]GestureType{GestureTap},
+		},
+		"double tap": {
+			true,
+			[]step{{down, 0}, {up, 0}, {down, 0}, {up, 100 * time.Millisecond}},
+			[]GestureType{GestureDoubleTap},
+		},
+		"tap and long press": {
+			true,
+			[]step{{down, 0}, {up, 0}, {down, 200 * time.Millisecond}, {up, 0}},
+			[]GestureType{GestureTap, GestureLongPress, GestureHoldRepeat, GestureHoldRepeat},
+		},
+		"long press": {
+			false,
+			[]step{{down, 200 * time.Millisecond}, {up, 100 * time.Millisecond}},
+			[]GestureType{GestureLongPress, GestureHoldRepeat, GestureHoldRepeat},
+		},
+		"disappear while pressed": {
+			false,
+			[]step{{down, 0}, {&WillDisappear{Context: "context"}, 150 * time.Millisecond}, {up, 0}},
+			nil,
+		},
+		"multi action": {
+			true,
+			[]step{
+				{&KeyDown{Context: "context", IsInMultiAction: true}, 0},
+				{&KeyUp{Context: "context", IsInMultiAction: true}, 0},
+			},
+			[]GestureType{GestureTap},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gestures := make(chan *Gesture, 16)
+			record := func(ctx context.Context, g *Gesture) error {
+				gestures <- g
+				return nil
+			}
+
+			cfg := GestureConfig{
+				OnTap:              record,
+				OnLongPress:        record,
+				OnHoldRepeat:       record,
+				LongPressThreshold: 50 * time.Millisecond,
+				DoubleTapInterval:  50 * time.Millisecond,
+				HoldRepeatInterval: 60 * time.Millisecond,
+			}
+			if tt.doubleTap {
+				cfg.OnDoubleTap = record
+			}
+			d := NewGestureDetector(cfg)
+			clock := &fakeClock{}
+			d.afterFunc = clock.afterFunc
+
+			for _, s := range tt.steps {
+				noError(t, d.Handle(context.Background(), s.ev))
+				clock.advance(s.advance)
+			}
+
+			var got []GestureType
+			repeat := 0
+			for len(gestures) > 0 {
+				g := <-gestures
+				equal(t, g.Context, InstanceID("context"))
+				if g.Type == GestureHoldRepeat {
+					repeat++
+					equal(t, g.Repeat, repeat)
+				}
+				got = append(got, g.Type)
+			}
+			equal(t, got, tt.want)
+		})
+	}
+}
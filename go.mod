@@ -1,7 +1,17 @@
 module github.com/morikuni/go-stream-deck-sdk
 
-go 1.17
+go 1.18
 
-require golang.org/x/net v0.0.0-20220127200216-cd36cc0744dd
+require (
+	github.com/google/go-cmp v0.5.7
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	github.com/srwiley/oksvg v0.0.0-20221011165216-be6e8873101c
+	github.com/srwiley/rasterx v0.0.0-20210519020934-456a8d69b780
+	golang.org/x/image v0.0.0-20211028202545-6944b10bf410
+	golang.org/x/net v0.0.0-20220127200216-cd36cc0744dd
+)
 
-require github.com/google/go-cmp v0.5.7 // indirect
+require (
+	golang.org/x/text v0.3.7 // indirect
+	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
+)
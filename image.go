@@ -0,0 +1,66 @@
+package streamdeck
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+
+	"golang.org/x/image/draw"
+
+	"github.com/morikuni/go-stream-deck-sdk/internal/imageutil"
+)
+
+// ImageSize is the size of an image in pixels.
+type ImageSize struct {
+	Width  int
+	Height int
+}
+
+func (s ImageSize) rect() image.Rectangle {
+	return image.Rect(0, 0, s.Width, s.Height)
+}
+
+var (
+	// ImageSizeKey is the size of a key image.
+	ImageSizeKey = ImageSize{Width: 72, Height: 72}
+	// ImageSizeKeyHighDPI is the size of a key image for Stream Deck XL or @2x images.
+	ImageSizeKeyHighDPI = ImageSize{Width: 144, Height: 144}
+	// ImageSizeTouchStrip is the size of an image for a dial on the touch strip of Stream Deck +.
+	ImageSizeTouchStrip = ImageSize{Width: 200, Height: 100}
+)
+
+// KeyImageSize returns the size of a key image for the device type.
+func KeyImageSize(dt DeviceType) ImageSize {
+	switch dt {
+	case DeviceTypeStreamDeckXL:
+		return ImageSizeKeyHighDPI
+	default:
+		return ImageSizeKey
+	}
+}
+
+// NewImageFromImage encodes img into a PNG image of the size.
+// The image is scaled to fit in the size keeping its aspect ratio,
+// and placed at the center.
+func NewImageFromImage(img image.Image, size ImageSize) (Image, error) {
+	if size.Width <= 0 || size.Height <= 0 {
+		return "", fmt.Errorf("invalid image size: %dx%d", size.Width, size.Height)
+	}
+
+	dst := image.NewNRGBA(size.rect())
+	draw.CatmullRom.Scale(dst, imageutil.FitRect(img.Bounds().Size(), image.Pt(size.Width, size.Height)), img, img.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, dst)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	return NewImage("png", buf.Bytes()), nil
+}
+
+// NewImageFromSVG returns an Image of the SVG document.
+func NewImageFromSVG(svg []byte) Image {
+	return NewImage("svg+xml", svg)
+}
@@ -0,0 +1,66 @@
+package streamdeck
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestNewImageFromImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 20, 10))
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 10; y++ {
+			src.Set(x, y, color.White)
+		}
+	}
+
+	for name, tt := range map[string]struct {
+		size ImageSize
+
+		opaque image.Rectangle
+	}{
+		"key": {
+			KeyImageSize(DeviceTypeStreamDeck),
+			image.Rect(0, 18, 72, 54),
+		},
+		"xl": {
+			KeyImageSize(DeviceTypeStreamDeckXL),
+			image.Rect(0, 36, 144, 108),
+		},
+		"touch strip": {
+			ImageSizeTouchStrip,
+			image.Rect(0, 0, 200, 100),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			img, err := NewImageFromImage(src, tt.size)
+			noError(t, err)
+
+			const prefix = "data:image/png;base64,"
+			if !strings.HasPrefix(string(img), prefix) {
+				t.Fatal("unexpected data URI:", img)
+			}
+			bs, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(string(img), prefix))
+			noError(t, err)
+			decoded, err := png.Decode(bytes.NewReader(bs))
+			noError(t, err)
+
+			equal(t, decoded.Bounds(), tt.size.rect())
+			// check the pixels at the center and the corner to see the image keeps its aspect ratio.
+			center := tt.opaque.Min.Add(tt.opaque.Size().Div(2))
+			_, _, _, a := decoded.At(center.X, center.Y).RGBA()
+			equal(t, a, uint32(0xffff))
+			_, _, _, a = decoded.At(0, 0).RGBA()
+			equal(t, a == 0, tt.opaque.Min != image.Point{})
+		})
+	}
+}
+
+func TestNewImageFromSVG(t *testing.T) {
+	img := NewImageFromSVG([]byte("<svg></svg>"))
+	equal(t, img, Image("data:image/svg+xml;base64,PHN2Zz48L3N2Zz4="))
+}
@@ -0,0 +1,20 @@
+// Package cliutil provides helpers shared by the commands of the SDK.
+package cliutil
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+// Describe lists the problems one per line if err is manifest.Problems,
+// prefixed by what is invalid, e.g. "invalid plugin". The other errors are
+// returned as is.
+func Describe(what string, err error) error {
+	var ps manifest.Problems
+	if errors.As(err, &ps) {
+		return fmt.Errorf("%s:\n%w", what, ps)
+	}
+	return err
+}
@@ -0,0 +1,10 @@
+// Package fsutil provides file system helpers shared by the packages of the SDK.
+package fsutil
+
+import "os"
+
+// IsFile reports whether path exists and is not a directory.
+func IsFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
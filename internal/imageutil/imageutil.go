@@ -0,0 +1,20 @@
+// Package imageutil provides image helpers shared by the packages of the SDK.
+package imageutil
+
+import "image"
+
+// FitRect returns the largest rectangle of the aspect ratio of src centered in size.
+// It returns the whole size if src is empty.
+func FitRect(src, size image.Point) image.Rectangle {
+	if src.X <= 0 || src.Y <= 0 {
+		return image.Rectangle{Max: size}
+	}
+
+	w, h := size.X, size.X*src.Y/src.X
+	if h > size.Y {
+		w, h = size.Y*src.X/src.Y, size.Y
+	}
+
+	x, y := (size.X-w)/2, (size.Y-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
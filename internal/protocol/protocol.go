@@ -0,0 +1,59 @@
+// Package protocol provides the envelopes of the messages exchanged with
+// the Stream Deck application, shared by plugins and property inspectors.
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EventPayload is the envelope of a received event.
+type EventPayload struct {
+	Event   string          `json:"event"`
+	Payload json.RawMessage `json:"payload"`
+	Raw     json.RawMessage `json:"-"`
+}
+
+func (ep *EventPayload) UnmarshalJSON(bs []byte) error {
+	var extractor struct {
+		Event   string          `json:"event"`
+		Payload json.RawMessage `json:"payload"`
+	}
+
+	err := json.Unmarshal(bs, &extractor)
+	if err != nil {
+		return err
+	}
+
+	ep.Event = extractor.Event
+	ep.Payload = extractor.Payload
+	ep.Raw = bs
+	return nil
+}
+
+// Bind unmarshals the payload and then the whole event into e, so that
+// the fields in the payload and the top level ones are both filled.
+func (ep EventPayload) Bind(e interface{}) error {
+	if len(ep.Payload) > 0 {
+		err := json.Unmarshal(ep.Payload, e)
+		if err != nil {
+			return fmt.Errorf("failed to bind event payload to %T: %w", e, err)
+		}
+	}
+
+	err := json.Unmarshal(ep.Raw, e)
+	if err != nil {
+		return fmt.Errorf("failed to bind event to %T: %w", e, err)
+	}
+
+	return nil
+}
+
+// CommandPayload is the envelope of a command to send.
+type CommandPayload struct {
+	Event   string          `json:"event"`
+	Context string          `json:"context,omitempty"`
+	Action  string          `json:"action,omitempty"`
+	Device  string          `json:"device,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
@@ -0,0 +1,68 @@
+package streamdeck
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+// Localizer looks up the strings in Localization of <lang>.json in the
+// plugin directory.
+type Localizer struct {
+	language manifest.Language
+	strings  map[string]string
+}
+
+// NewLocalizer loads the localization file of the language in pluginDir.
+// The language is usually Application.Language of Conn.Info.
+// A missing file is not an error, and the keys are returned as is.
+func NewLocalizer(pluginDir, language string) (*Localizer, error) {
+	l := &Localizer{language: manifest.Language(language)}
+	if language == "" {
+		return l, nil
+	}
+
+	loc, err := manifest.LoadLocalization(manifest.LocalizationPath(pluginDir, l.language))
+	if errors.Is(err, fs.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	l.strings = loc.Localization
+	return l, nil
+}
+
+// NewLocalizerFromConn loads the localization file of the application
+// language in the registration info.
+func NewLocalizerFromConn(pluginDir string, conn *Conn) (*Localizer, error) {
+	info, err := conn.Info()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the application language: %w", err)
+	}
+	if info == nil {
+		return NewLocalizer(pluginDir, "")
+	}
+	return NewLocalizer(pluginDir, info.Application.Language)
+}
+
+// Language returns the language of the strings.
+func (l *Localizer) Language() string {
+	return string(l.language)
+}
+
+// Localize returns the string for the key, or the key itself if it is not
+// localized.
+func (l *Localizer) Localize(key string) string {
+	if s, ok := l.strings[key]; ok {
+		return s
+	}
+	return key
+}
+
+// Localizef localizes the format and formats it.
+func (l *Localizer) Localizef(format string, a ...interface{}) string {
+	return fmt.Sprintf(l.Localize(format), a...)
+}
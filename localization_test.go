@@ -0,0 +1,84 @@
+package streamdeck
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/morikuni/go-stream-deck-sdk/streamdecktest"
+)
+
+func TestNewLocalizerFromConn(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "de.json"), []byte(`{
+		"Name": "Zähler",
+		"com.example.counter": {"Name": "Zähler"},
+		"Localization": {"Count: %d": "Anzahl: %d", "Reset": "Zurücksetzen"}
+	}`), 0o644)
+	noError(t, err)
+
+	srv := streamdecktest.NewServer()
+	t.Cleanup(srv.Close)
+	conn, err := Dial(
+		WithPort(srv.Port()),
+		WithPluginUUID("pluginUUID"),
+		WithRegisterEvent("registerPlugin"),
+		WithRegistrationInfo(`{
+			"application": {"font": ".AppleSystemUIFont", "language": "de", "platform": "mac", "platformVersion": "13.0.0", "version": "6.0.0"},
+			"plugin": {"uuid": "com.example", "version": "1.0"},
+			"devicePixelRatio": 2,
+			"devices": [{"id": "device1", "name": "Stream Deck +", "size": {"columns": 4, "rows": 2}, "type": 7}]
+		}`),
+	)
+	noError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	_, err = srv.Registration(time.Second)
+	noError(t, err)
+
+	info, err := conn.Info()
+	noError(t, err)
+	equal(t, info, &RegistrationInfo{
+		Application: ApplicationInfo{
+			Font:            ".AppleSystemUIFont",
+			Language:        "de",
+			Platform:        "mac",
+			PlatformVersion: "13.0.0",
+			Version:         "6.0.0",
+		},
+		Plugin:           PluginInfo{UUID: "com.example", Version: "1.0"},
+		DevicePixelRatio: 2,
+		Devices: []RegisteredDevice{
+			{ID: "device1", DeviceInfo: DeviceInfo{Name: "Stream Deck +", Type: DeviceTypeStreamDeckPlus, Size: Size{Rows: 2, Columns: 4}}},
+		},
+	})
+
+	l, err := NewLocalizerFromConn(dir, conn)
+	noError(t, err)
+	equal(t, l.Language(), "de")
+	equal(t, l.Localize("Reset"), "Zurücksetzen")
+	equal(t, l.Localize("Unknown"), "Unknown")
+	equal(t, l.Localizef("Count: %d", 3), "Anzahl: 3")
+}
+
+func TestNewLocalizer_NotFound(t *testing.T) {
+	l, err := NewLocalizer(t.TempDir(), "ja")
+	noError(t, err)
+	equal(t, l.Localize("Reset"), "Reset")
+}
+
+func TestConn_InvalidRegistrationInfo(t *testing.T) {
+	srv := streamdecktest.NewServer()
+	t.Cleanup(srv.Close)
+
+	// an unexpected info must not prevent the plugin from connecting.
+	conn, err := Dial(WithPort(srv.Port()), WithPluginUUID("pluginUUID"), WithRegisterEvent("registerPlugin"), WithRegistrationInfo(`{"devicePixelRatio": "2"}`))
+	noError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = conn.Info()
+	if err == nil {
+		t.Error("want error")
+	}
+	equal(t, string(conn.RawInfo()), `{"devicePixelRatio": "2"}`)
+}
@@ -0,0 +1,16 @@
+package manifest
+
+// ActionSource is a type which declares an action in the manifest,
+// e.g. streamdeck.Action.
+type ActionSource interface {
+	ManifestAction() Action
+}
+
+// FromActions returns the Actions of the manifest declared by the sources.
+func FromActions(sources ...ActionSource) []Action {
+	actions := make([]Action, len(sources))
+	for i, s := range sources {
+		actions[i] = s.ManifestAction()
+	}
+	return actions
+}
@@ -0,0 +1,357 @@
+package manifest
+
+import (
+	"strings"
+)
+
+// Default values filled by Builder.
+const (
+	DefaultVersion                = "1.0.0"
+	DefaultSDKVersion             = 2
+	DefaultMacMinimumVersion      = "10.15"
+	DefaultWindowsMinimumVersion  = "10"
+	DefaultSoftwareMinimumVersion = "5.0"
+
+	// DefaultActionIconSuffix is appended to the plugin icon for the default action icon.
+	DefaultActionIconSuffix = "-action"
+	// DefaultKeyImageSuffix is appended to the action icon for the default state image.
+	DefaultKeyImageSuffix = "-key"
+)
+
+const windowsExecutableFileExtension = ".exe"
+
+// Builder builds a Manifest with sensible defaults.
+//
+//	m, err := manifest.New("Hello World").
+//		Author("morikuni").
+//		Icon("icon").
+//		Binary("helloworld").
+//		Action("com.github.morikuni.helloworld", func(a *manifest.ActionBuilder) {
+//			a.Name("Hello World").Tooltip("Say hello")
+//		}).
+//		Build()
+type Builder struct {
+	m      Manifest
+	binary string
+}
+
+// New starts building a manifest of the plugin named name.
+// Version, SDKVersion, OS and Software have the default values,
+// and Description defaults to the name.
+func New(name string) *Builder {
+	return &Builder{
+		m: Manifest{
+			Name:       name,
+			Version:    DefaultVersion,
+			SDKVersion: DefaultSDKVersion,
+			Software:   Software{MinimumVersion: DefaultSoftwareMinimumVersion},
+		},
+	}
+}
+
+func (b *Builder) Author(author string) *Builder {
+	b.m.Author = author
+	return b
+}
+
+func (b *Builder) Description(description string) *Builder {
+	b.m.Description = description
+	return b
+}
+
+func (b *Builder) Version(version string) *Builder {
+	b.m.Version = version
+	return b
+}
+
+// Icon sets the plugin icon, which is also the base name of the default action icons.
+func (b *Builder) Icon(icon string) *Builder {
+	b.m.Icon = icon
+	return b
+}
+
+func (b *Builder) URL(url string) *Builder {
+	b.m.URL = OptionalString(url)
+	return b
+}
+
+// Category sets the category name and its icon. icon can be empty.
+func (b *Builder) Category(name, icon string) *Builder {
+	b.m.Category = OptionalString(name)
+	if icon != "" {
+		b.m.CategoryIcon = OptionalString(icon)
+	}
+	return b
+}
+
+// Binary sets CodePath to the executable name, and CodePathMac and
+// CodePathWin to the one for each platform in OS, e.g. name.exe for Windows.
+func (b *Builder) Binary(name string) *Builder {
+	b.binary = name
+	return b
+}
+
+// CodePath sets CodePath explicitly. Use Binary for Go plugins.
+func (b *Builder) CodePath(path string) *Builder {
+	b.binary = ""
+	b.m.CodePath = path
+	return b
+}
+
+func (b *Builder) PropertyInspector(path string) *Builder {
+	b.m.PropertyInspectorPath = OptionalString(path)
+	return b
+}
+
+func (b *Builder) DefaultWindowSize(width, height int) *Builder {
+	b.m.DefaultWindowSize = []int{width, height}
+	return b
+}
+
+// OS adds the supported platform. If OS is never called, both mac and
+// windows with the default minimum versions are supported.
+func (b *Builder) OS(platform Platform, minimumVersion string) *Builder {
+	b.m.OS = append(b.m.OS, OS{Platform: platform, MinimumVersion: minimumVersion})
+	return b
+}
+
+// SoftwareMinimumVersion sets the minimum version of the Stream Deck app.
+func (b *Builder) SoftwareMinimumVersion(version string) *Builder {
+	b.m.Software.MinimumVersion = version
+	return b
+}
+
+func (b *Builder) Profile(name string, deviceType DeviceType) *Builder {
+	b.m.Profiles = append(b.m.Profiles, Profile{Name: name, DeviceType: deviceType})
+	return b
+}
+
+// MonitorApplications adds applications to ApplicationsToMonitor.
+// apps are bundle identifiers for mac and executable names for windows.
+func (b *Builder) MonitorApplications(platform Platform, apps ...string) *Builder {
+	if b.m.ApplicationsToMonitor == nil {
+		b.m.ApplicationsToMonitor = &ApplicationsToMonitor{}
+	}
+	switch platform {
+	case PlatformMac:
+		b.m.ApplicationsToMonitor.Mac = append(b.m.ApplicationsToMonitor.Mac, apps...)
+	case PlatformWindows:
+		b.m.ApplicationsToMonitor.Windows = append(b.m.ApplicationsToMonitor.Windows, apps...)
+	}
+	return b
+}
+
+// Action adds the action identified by uuid configured by f. f can be nil.
+func (b *Builder) Action(uuid string, f func(a *ActionBuilder)) *Builder {
+	ab := &ActionBuilder{a: Action{UUID: uuid}}
+	if f != nil {
+		f(ab)
+	}
+	b.m.Actions = append(b.m.Actions, ab.a)
+	return b
+}
+
+// Actions adds the actions declared by the sources. See FromActions.
+func (b *Builder) Actions(sources ...ActionSource) *Builder {
+	b.m.Actions = append(b.m.Actions, FromActions(sources...)...)
+	return b
+}
+
+// Build fills the defaults and returns the validated manifest.
+// The returned error is Problems if the manifest is invalid.
+func (b *Builder) Build() (*Manifest, error) {
+	m := b.m
+	if m.Description == "" {
+		m.Description = m.Name
+	}
+	if len(m.OS) == 0 {
+		m.OS = []OS{
+			{Platform: PlatformMac, MinimumVersion: DefaultMacMinimumVersion},
+			{Platform: PlatformWindows, MinimumVersion: DefaultWindowsMinimumVersion},
+		}
+	}
+	if b.binary != "" {
+		m.CodePath = b.binary
+		for _, o := range m.OS {
+			switch o.Platform {
+			case PlatformMac:
+				m.CodePathMac = OptionalString(b.binary)
+			case PlatformWindows:
+				win := b.binary
+				if !strings.HasSuffix(win, windowsExecutableFileExtension) {
+					win += windowsExecutableFileExtension
+				}
+				m.CodePathWin = OptionalString(win)
+			}
+		}
+	}
+
+	m.Actions = make([]Action, len(b.m.Actions))
+	for i, a := range b.m.Actions {
+		if a.Icon == "" && m.Icon != "" {
+			a.Icon = m.Icon + DefaultActionIconSuffix
+		}
+		if len(a.States) == 0 && a.Icon != "" {
+			a.States = []State{{Image: a.Icon + DefaultKeyImageSuffix}}
+		}
+		m.Actions[i] = a
+	}
+
+	err := m.Validate().Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// ActionBuilder configures an action in Builder.Action.
+// Icon defaults to the plugin icon with DefaultActionIconSuffix, and States
+// defaults to a single state whose image is the action icon with DefaultKeyImageSuffix.
+// They are separate images because the sizes of the images differ.
+type ActionBuilder struct {
+	a Action
+}
+
+func (b *ActionBuilder) Name(name string) *ActionBuilder {
+	b.a.Name = name
+	return b
+}
+
+func (b *ActionBuilder) Icon(icon string) *ActionBuilder {
+	b.a.Icon = icon
+	return b
+}
+
+func (b *ActionBuilder) Tooltip(tooltip string) *ActionBuilder {
+	b.a.Tooltip = OptionalString(tooltip)
+	return b
+}
+
+func (b *ActionBuilder) PropertyInspector(path string) *ActionBuilder {
+	b.a.PropertyInspectorPath = OptionalString(path)
+	return b
+}
+
+func (b *ActionBuilder) SupportedInMultiActions(supported bool) *ActionBuilder {
+	b.a.SupportedInMultiActions = OptionalBool(supported)
+	return b
+}
+
+// OnlyMultiActions makes the action available only in multi-actions.
+func (b *ActionBuilder) OnlyMultiActions(only bool) *ActionBuilder {
+	b.a.OnlyMultiActions = OptionalBool(only)
+	return b
+}
+
+func (b *ActionBuilder) VisibleInActionsList(visible bool) *ActionBuilder {
+	b.a.VisibleInActionsList = OptionalBool(visible)
+	return b
+}
+
+func (b *ActionBuilder) DisableCaching(disable bool) *ActionBuilder {
+	b.a.DisableCaching = OptionalBool(disable)
+	return b
+}
+
+func (b *ActionBuilder) DisableAutomaticStates(disable bool) *ActionBuilder {
+	b.a.DisableAutomaticStates = OptionalBool(disable)
+	return b
+}
+
+func (b *ActionBuilder) UserTitleEnabled(enabled bool) *ActionBuilder {
+	b.a.UserTitleEnabled = OptionalBool(enabled)
+	return b
+}
+
+func (b *ActionBuilder) Controllers(controllers ...Controller) *ActionBuilder {
+	b.a.Controllers = controllers
+	return b
+}
+
+// Encoder sets the encoder configuration, and adds ControllerEncoder to
+// Controllers unless it is already there.
+func (b *ActionBuilder) Encoder(e Encoder) *ActionBuilder {
+	b.a.Encoder = &e
+	for _, c := range b.a.Controllers {
+		if c == ControllerEncoder {
+			return b
+		}
+	}
+	b.a.Controllers = append(b.a.Controllers, ControllerEncoder)
+	return b
+}
+
+// State adds a state with the image configured by f. f can be nil.
+func (b *ActionBuilder) State(image string, f func(s *StateBuilder)) *ActionBuilder {
+	sb := &StateBuilder{s: State{Image: image}}
+	if f != nil {
+		f(sb)
+	}
+	b.a.States = append(b.a.States, sb.s)
+	return b
+}
+
+// StateBuilder configures a state in ActionBuilder.State.
+type StateBuilder struct {
+	s State
+}
+
+func (b *StateBuilder) Name(name string) *StateBuilder {
+	b.s.Name = OptionalString(name)
+	return b
+}
+
+func (b *StateBuilder) MultiActionImage(image string) *StateBuilder {
+	b.s.MultiActionImage = OptionalString(image)
+	return b
+}
+
+func (b *StateBuilder) Title(title string) *StateBuilder {
+	b.s.Title = OptionalString(title)
+	return b
+}
+
+func (b *StateBuilder) ShowTitle(show bool) *StateBuilder {
+	b.s.ShowTitle = OptionalBool(show)
+	return b
+}
+
+func (b *StateBuilder) TitleColor(color string) *StateBuilder {
+	b.s.TitleColor = OptionalString(color)
+	return b
+}
+
+// TitleAlignment sets the alignment, which is top, middle or bottom.
+func (b *StateBuilder) TitleAlignment(alignment string) *StateBuilder {
+	b.s.TitleAlignment = OptionalString(alignment)
+	return b
+}
+
+// SupportedInMultiActions overrides the one of the action for the state.
+func (b *StateBuilder) SupportedInMultiActions(supported bool) *StateBuilder {
+	b.s.SupportedInMultiActions = OptionalBool(supported)
+	return b
+}
+
+// PropertyInspector overrides the property inspector of the action for the state.
+func (b *StateBuilder) PropertyInspector(path string) *StateBuilder {
+	b.s.PropertyInspectorPath = OptionalString(path)
+	return b
+}
+
+// Font sets the font of the title. Empty family or style and zero size are
+// left unset.
+func (b *StateBuilder) Font(family, style string, size int, underline bool) *StateBuilder {
+	if family != "" {
+		b.s.FontFamily = OptionalString(family)
+	}
+	if style != "" {
+		b.s.FontStyle = OptionalString(style)
+	}
+	if size != 0 {
+		b.s.FontSize = OptionalInt(size)
+	}
+	b.s.FontUnderline = OptionalBool(underline)
+	return b
+}
@@ -0,0 +1,146 @@
+package manifest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestBuilder(t *testing.T) {
+	m, err := New("Plugin").
+		Author("author").
+		Icon("images/icon").
+		Binary("plugin").
+		Category("Category", "").
+		Action("com.example.plugin.action", func(a *ActionBuilder) {
+			a.Name("Action").Tooltip("tooltip")
+		}).
+		Action("com.example.plugin.toggle", func(a *ActionBuilder) {
+			a.Name("Toggle").Icon("images/toggle").OnlyMultiActions(true).
+				State("images/off", nil).
+				State("images/on", func(s *StateBuilder) {
+					s.Title("ON").TitleAlignment("bottom").
+						SupportedInMultiActions(false).
+						PropertyInspector("pi/on.html")
+				})
+		}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Manifest{
+		Actions: []Action{
+			{
+				Icon:    "images/icon-action",
+				Name:    "Action",
+				States:  []State{{Image: "images/icon-action-key"}},
+				Tooltip: OptionalString("tooltip"),
+				UUID:    "com.example.plugin.action",
+			},
+			{
+				Icon: "images/toggle",
+				Name: "Toggle",
+				States: []State{
+					{Image: "images/off"},
+					{
+						Image:                   "images/on",
+						Title:                   OptionalString("ON"),
+						TitleAlignment:          OptionalString("bottom"),
+						SupportedInMultiActions: OptionalBool(false),
+						PropertyInspectorPath:   OptionalString("pi/on.html"),
+					},
+				},
+				OnlyMultiActions: OptionalBool(true),
+				UUID:             "com.example.plugin.toggle",
+			},
+		},
+		Author:      "author",
+		Category:    OptionalString("Category"),
+		CodePath:    "plugin",
+		CodePathMac: OptionalString("plugin"),
+		CodePathWin: OptionalString("plugin.exe"),
+		Description: "Plugin",
+		Icon:        "images/icon",
+		Name:        "Plugin",
+		Version:     DefaultVersion,
+		SDKVersion:  DefaultSDKVersion,
+		OS: []OS{
+			{Platform: PlatformMac, MinimumVersion: DefaultMacMinimumVersion},
+			{Platform: PlatformWindows, MinimumVersion: DefaultWindowsMinimumVersion},
+		},
+		Software: Software{MinimumVersion: DefaultSoftwareMinimumVersion},
+	}
+	if diff := cmp.Diff(want, m, cmpopts.IgnoreUnexported(State{})); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestBuilder_Binary(t *testing.T) {
+	m, err := New("Plugin").
+		Author("author").
+		Icon("icon").
+		Binary("plugin").
+		OS(PlatformMac, "10.11").
+		Action("com.example.plugin.action", func(a *ActionBuilder) { a.Name("Action") }).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.CodePathMac == nil || *m.CodePathMac != "plugin" {
+		t.Errorf("unexpected CodePathMac: %v", m.CodePathMac)
+	}
+	if m.CodePathWin != nil {
+		t.Errorf("want no CodePathWin for mac only plugin: %v", *m.CodePathWin)
+	}
+}
+
+func TestBuilder_Invalid(t *testing.T) {
+	_, err := New("Plugin").
+		Icon("icon").
+		Binary("plugin").
+		Action("Action", nil).
+		Build()
+
+	var ps Problems
+	if !errors.As(err, &ps) {
+		t.Fatalf("want Problems but got %v", err)
+	}
+	want := Problems{
+		{Path: "$.Author", Message: "required"},
+		{Path: "$.Actions[0].Name", Message: "required"},
+		{Path: "$.Actions[0].UUID", Message: `must be a reverse-DNS format with lowercase alphanumeric characters, hyphens and periods: "Action"`},
+	}
+	if diff := cmp.Diff(want, ps); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+type actionSource Action
+
+func (a actionSource) ManifestAction() Action {
+	return Action(a)
+}
+
+func TestBuilder_Actions(t *testing.T) {
+	m, err := New("Plugin").
+		Author("author").
+		Icon("icon").
+		Binary("plugin").
+		Actions(actionSource{Name: "A", UUID: "com.example.plugin.a"}, actionSource{Name: "B", UUID: "com.example.plugin.b"}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Action{
+		{Icon: "icon-action", Name: "A", States: []State{{Image: "icon-action-key"}}, UUID: "com.example.plugin.a"},
+		{Icon: "icon-action", Name: "B", States: []State{{Image: "icon-action-key"}}, UUID: "com.example.plugin.b"},
+	}
+	if diff := cmp.Diff(want, m.Actions, cmpopts.IgnoreUnexported(State{})); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,130 @@
+package manifest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestLoad_RoundTrip(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no testdata")
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			m, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := json.Marshal(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotV, wantV interface{}
+			err = json.Unmarshal(got, &gotV)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = json.Unmarshal(want, &wantV)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(wantV, gotV); diff != "" {
+				t.Errorf("(-want, +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(`{
+		"Name": "Plugin",
+		"SDKVersion": 2,
+		"Unknown": {"a": 1},
+		"Actions": [{"UUID": "com.example.plugin.action", "States": [{"Image": "state", "Unknown": true}], "Unknown": "x"}],
+		"ApplicationsToMonitor": {"mac": ["com.apple.Music"], "windows": ["Spotify.exe"]}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Manifest{
+		Name:       "Plugin",
+		SDKVersion: 2,
+		Actions: []Action{
+			{
+				UUID: "com.example.plugin.action",
+				States: []State{
+					{Image: "state", Extra: map[string]json.RawMessage{"Unknown": json.RawMessage(`true`)}},
+				},
+				Extra: map[string]json.RawMessage{"Unknown": json.RawMessage(`"x"`)},
+			},
+		},
+		ApplicationsToMonitor: &ApplicationsToMonitor{
+			Mac:     []string{"com.apple.Music"},
+			Windows: []string{"Spotify.exe"},
+		},
+		Extra: map[string]json.RawMessage{"Unknown": json.RawMessage(`{"a": 1}`)},
+	}
+	if diff := cmp.Diff(want, m, cmpopts.IgnoreUnexported(State{})); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestState_FontSize(t *testing.T) {
+	for name, tt := range map[string]struct {
+		json string
+
+		want int
+	}{
+		"number": {`{"Image":"state","FontSize":16}`, 16},
+		"string": {`{"Image":"state","FontSize":"16"}`, 16},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var s State
+			err := json.Unmarshal([]byte(tt.json), &s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.FontSize == nil || *s.FontSize != tt.want {
+				t.Fatalf("want %d but got %v", tt.want, s.FontSize)
+			}
+
+			got, err := json.Marshal(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.json {
+				t.Errorf("want %s but got %s", tt.json, got)
+			}
+		})
+	}
+
+	var s State
+	err := json.Unmarshal([]byte(`{"FontSize":"large"}`), &s)
+	if err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse([]byte(`{"Actions": {}}`))
+	if err == nil {
+		t.Error("want error")
+	}
+}
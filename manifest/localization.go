@@ -0,0 +1,202 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Language is the language code of the Stream Deck application, which is
+// also the name of the localization file without .json.
+type Language string
+
+const (
+	LanguageEnglish            Language = "en"
+	LanguageGerman             Language = "de"
+	LanguageSpanish            Language = "es"
+	LanguageFrench             Language = "fr"
+	LanguageJapanese           Language = "ja"
+	LanguageKorean             Language = "ko"
+	LanguageChineseSimplified  Language = "zh_CN"
+	LanguageChineseTraditional Language = "zh_TW"
+)
+
+// SupportedLanguages is the languages of the localization files.
+var SupportedLanguages = []Language{
+	LanguageEnglish,
+	LanguageGerman,
+	LanguageSpanish,
+	LanguageFrench,
+	LanguageJapanese,
+	LanguageKorean,
+	LanguageChineseSimplified,
+	LanguageChineseTraditional,
+}
+
+// Localization is the content of <lang>.json placed next to manifest.json.
+// The fields of the manifest are localized by Name, Description, Category
+// and Actions, and the strings for the property inspector and the plugin are
+// in Localization.
+type Localization struct {
+	Name        string
+	Description string
+	Category    string
+	// Actions is keyed by the action UUID.
+	Actions map[string]ActionLocalization
+	// Localization is the arbitrary strings keyed by the English text or an ID.
+	Localization map[string]string
+}
+
+type ActionLocalization struct {
+	Name    string              `json:",omitempty"`
+	Tooltip string              `json:",omitempty"`
+	States  []StateLocalization `json:",omitempty"`
+}
+
+type StateLocalization struct {
+	Name string `json:",omitempty"`
+}
+
+type localizationFields struct {
+	Name         string            `json:",omitempty"`
+	Description  string            `json:",omitempty"`
+	Category     string            `json:",omitempty"`
+	Localization map[string]string `json:",omitempty"`
+}
+
+func (l *Localization) UnmarshalJSON(data []byte) error {
+	var f localizationFields
+	var actions map[string]json.RawMessage
+	err := unmarshalObject(data, &f, &actions)
+	if err != nil {
+		return err
+	}
+
+	*l = Localization{
+		Name:         f.Name,
+		Description:  f.Description,
+		Category:     f.Category,
+		Localization: f.Localization,
+	}
+	for uuid, raw := range actions {
+		var a ActionLocalization
+		err := json.Unmarshal(raw, &a)
+		if err != nil {
+			return fmt.Errorf("invalid action %s: %w", uuid, err)
+		}
+		if l.Actions == nil {
+			l.Actions = make(map[string]ActionLocalization, len(actions))
+		}
+		l.Actions[uuid] = a
+	}
+	return nil
+}
+
+func (l Localization) MarshalJSON() ([]byte, error) {
+	actions := make(map[string]json.RawMessage, len(l.Actions))
+	for uuid, a := range l.Actions {
+		bs, err := json.Marshal(a)
+		if err != nil {
+			return nil, err
+		}
+		actions[uuid] = bs
+	}
+
+	return marshalObject(localizationFields{
+		Name:         l.Name,
+		Description:  l.Description,
+		Category:     l.Category,
+		Localization: l.Localization,
+	}, actions)
+}
+
+// LocalizationPath returns the path of the localization file of the language
+// in the plugin directory.
+func LocalizationPath(dir string, lang Language) string {
+	return filepath.Join(dir, string(lang)+".json")
+}
+
+// LoadLocalization reads the localization file at the path.
+func LoadLocalization(path string) (*Localization, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read localization: %w", err)
+	}
+
+	var l Localization
+	err = json.Unmarshal(data, &l)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse localization: %w", err)
+	}
+	return &l, nil
+}
+
+// WriteLocalizations writes <lang>.json of each language into the plugin
+// directory.
+func WriteLocalizations(dir string, ls map[Language]*Localization) error {
+	langs := make([]string, 0, len(ls))
+	for lang := range ls {
+		langs = append(langs, string(lang))
+	}
+	sort.Strings(langs)
+
+	for _, lang := range langs {
+		bs, err := json.MarshalIndent(ls[Language(lang)], "", "\t")
+		if err != nil {
+			return fmt.Errorf("failed to encode localization %s: %w", lang, err)
+		}
+		err = os.WriteFile(LocalizationPath(dir, Language(lang)), append(bs, '\n'), 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to write localization %s: %w", lang, err)
+		}
+	}
+	return nil
+}
+
+// ValidateLocalization checks that the localization only refers to the
+// actions and states declared in the manifest.
+// The paths of the problems are in the localization file.
+func (m *Manifest) ValidateLocalization(lang Language, l *Localization) Problems {
+	var v validator
+
+	supported := false
+	for _, sl := range SupportedLanguages {
+		supported = supported || lang == sl
+	}
+	if !supported {
+		v.add("$", "unsupported language %q: supported languages are %v", lang, SupportedLanguages)
+	}
+
+	if l.Category != "" && m.Category == nil {
+		v.add("$.Category", "Category is not in the manifest")
+	}
+
+	actions := make(map[string]*Action, len(m.Actions))
+	for i := range m.Actions {
+		actions[m.Actions[i].UUID] = &m.Actions[i]
+	}
+	uuids := make([]string, 0, len(l.Actions))
+	for uuid := range l.Actions {
+		uuids = append(uuids, uuid)
+	}
+	sort.Strings(uuids)
+	for _, uuid := range uuids {
+		path := fmt.Sprintf("$[%q]", uuid)
+		a, ok := actions[uuid]
+		if !ok {
+			v.add(path, "action is not in the manifest")
+			continue
+		}
+		la := l.Actions[uuid]
+		if la.Tooltip != "" && a.Tooltip == nil {
+			v.add(path+".Tooltip", "Tooltip is not in the manifest")
+		}
+		if len(la.States) > len(a.States) {
+			v.add(path+".States", "has %d states but the manifest has %d", len(la.States), len(a.States))
+		}
+	}
+
+	return v.problems
+}
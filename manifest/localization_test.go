@@ -0,0 +1,128 @@
+package manifest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLocalization_JSON(t *testing.T) {
+	data := []byte(`{
+		"Description": "Zählt, wie oft die Taste gedrückt wurde.",
+		"Name": "Zähler",
+		"com.example.plugin.action": {
+			"Name": "Zähler",
+			"Tooltip": "Wie oft haben Sie die Taste gedrückt?",
+			"States": [{"Name": "Aus"}, {"Name": "An"}]
+		},
+		"Localization": {"Reset": "Zurücksetzen"}
+	}`)
+
+	var l Localization
+	err := json.Unmarshal(data, &l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Localization{
+		Name:        "Zähler",
+		Description: "Zählt, wie oft die Taste gedrückt wurde.",
+		Actions: map[string]ActionLocalization{
+			"com.example.plugin.action": {
+				Name:    "Zähler",
+				Tooltip: "Wie oft haben Sie die Taste gedrückt?",
+				States:  []StateLocalization{{Name: "Aus"}, {Name: "An"}},
+			},
+		},
+		Localization: map[string]string{"Reset": "Zurücksetzen"},
+	}
+	if diff := cmp.Diff(want, l); diff != "" {
+		t.Fatalf("(-want, +got)\n%s", diff)
+	}
+
+	got, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotV, wantV interface{}
+	if err := json.Unmarshal(got, &gotV); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &wantV); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wantV, gotV); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestWriteLocalizations(t *testing.T) {
+	dir := t.TempDir()
+	ls := map[Language]*Localization{
+		LanguageGerman:   {Name: "Zähler", Localization: map[string]string{"Reset": "Zurücksetzen"}},
+		LanguageJapanese: {Name: "カウンター"},
+	}
+	err := WriteLocalizations(dir, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for lang, want := range ls {
+		got, err := LoadLocalization(LocalizationPath(dir, lang))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s: (-want, +got)\n%s", lang, diff)
+		}
+	}
+}
+
+func TestManifest_ValidateLocalization(t *testing.T) {
+	m := validManifest()
+	l := &Localization{
+		Category: "Kategorie",
+		Actions: map[string]ActionLocalization{
+			"com.example.plugin.action":  {Name: "Aktion", Tooltip: "Tipp", States: []StateLocalization{{}, {}}},
+			"com.example.plugin.unknown": {Name: "Unbekannt"},
+		},
+	}
+
+	got := m.ValidateLocalization("xx", l)
+	want := Problems{
+		{Path: "$", Message: `unsupported language "xx": supported languages are [en de es fr ja ko zh_CN zh_TW]`},
+		{Path: "$.Category", Message: "Category is not in the manifest"},
+		{Path: `$["com.example.plugin.action"].Tooltip`, Message: "Tooltip is not in the manifest"},
+		{Path: `$["com.example.plugin.action"].States`, Message: "has 2 states but the manifest has 1"},
+		{Path: `$["com.example.plugin.unknown"]`, Message: "action is not in the manifest"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
+
+func TestManifest_ValidateDir_Localization(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"plugin", "images/icon.png", "images/action.png", "images/state.png"} {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "de.json"), []byte(`{"com.example.plugin.other": {"Name": "Andere"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := validManifest().ValidateDir(dir)
+	want := Problems{
+		{Path: `de.json:$["com.example.plugin.other"]`, Message: "action is not in the manifest"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want, +got)\n%s", diff)
+	}
+}
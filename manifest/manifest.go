@@ -4,6 +4,9 @@ package manifest
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 )
 
 type Manifest struct {
@@ -26,6 +29,20 @@ type Manifest struct {
 	OS                    []OS
 	Software              Software
 	ApplicationsToMonitor *ApplicationsToMonitor `json:",omitempty"`
+	Nodejs                *Nodejs                `json:",omitempty"`
+
+	// Extra is the keys unknown to this package, which are kept for forward compatibility.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *Manifest) UnmarshalJSON(data []byte) error {
+	type alias Manifest
+	return unmarshalObject(data, (*alias)(m), &m.Extra)
+}
+
+func (m Manifest) MarshalJSON() ([]byte, error) {
+	type alias Manifest
+	return marshalObject(alias(m), m.Extra)
 }
 
 type Action struct {
@@ -37,6 +54,29 @@ type Action struct {
 	Tooltip                 *string `json:",omitempty"`
 	UUID                    string
 	VisibleInActionsList    *bool `json:",omitempty"`
+	DisableCaching          *bool `json:",omitempty"`
+	DisableAutomaticStates  *bool `json:",omitempty"`
+	UserTitleEnabled        *bool `json:",omitempty"`
+	OnlyMultiActions        *bool `json:",omitempty"`
+	// Controllers is the controllers the action can be assigned to.
+	// The default is Keypad only.
+	Controllers []Controller `json:",omitempty"`
+	// Encoder is the configuration for dials and the touch strip of
+	// Stream Deck +, which requires ControllerEncoder in Controllers.
+	Encoder *Encoder `json:",omitempty"`
+
+	// Extra is the unknown keys. See Manifest.Extra.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (a *Action) UnmarshalJSON(data []byte) error {
+	type alias Action
+	return unmarshalObject(data, (*alias)(a), &a.Extra)
+}
+
+func (a Action) MarshalJSON() ([]byte, error) {
+	type alias Action
+	return marshalObject(alias(a), a.Extra)
 }
 
 type State struct {
@@ -51,6 +91,120 @@ type State struct {
 	FontStyle        *string `json:",omitempty"`
 	FontSize         *int    `json:",omitempty"`
 	FontUnderline    *bool   `json:",omitempty"`
+	// SupportedInMultiActions and PropertyInspectorPath override
+	// the ones of the action for the state.
+	SupportedInMultiActions *bool   `json:",omitempty"`
+	PropertyInspectorPath   *string `json:",omitempty"`
+
+	// Extra is the unknown keys. See Manifest.Extra.
+	Extra map[string]json.RawMessage `json:"-"`
+
+	// fontSizeString is true if FontSize was written as a string like "16",
+	// which is common in the published manifests.
+	fontSizeString bool
+}
+
+func (s *State) UnmarshalJSON(data []byte) error {
+	type alias State
+
+	var raw map[string]json.RawMessage
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	s.fontSizeString = false
+	for k, v := range raw {
+		if !strings.EqualFold(k, "FontSize") || len(v) == 0 || v[0] != '"' {
+			continue
+		}
+		var size string
+		err = json.Unmarshal(v, &size)
+		if err != nil {
+			return err
+		}
+		if _, err := strconv.Atoi(size); err != nil {
+			return fmt.Errorf("invalid FontSize: %q", size)
+		}
+		raw[k] = json.RawMessage(size)
+		s.fontSizeString = true
+		data, err = json.Marshal(raw)
+		if err != nil {
+			return err
+		}
+	}
+
+	return unmarshalObject(data, (*alias)(s), &s.Extra)
+}
+
+func (s State) MarshalJSON() ([]byte, error) {
+	type alias State
+	if !s.fontSizeString || s.FontSize == nil {
+		return marshalObject(alias(s), s.Extra)
+	}
+
+	extra := make(map[string]json.RawMessage, len(s.Extra)+1)
+	for k, v := range s.Extra {
+		extra[k] = v
+	}
+	extra["FontSize"] = json.RawMessage(strconv.Quote(strconv.Itoa(*s.FontSize)))
+	s.FontSize = nil
+	return marshalObject(alias(s), extra)
+}
+
+type Controller string
+
+const (
+	ControllerKeypad  Controller = "Keypad"
+	ControllerEncoder Controller = "Encoder"
+)
+
+type Encoder struct {
+	Background         *string             `json:"background,omitempty"`
+	Icon               *string             `json:",omitempty"`
+	Layout             *string             `json:"layout,omitempty"`
+	StackColor         *string             `json:",omitempty"`
+	TriggerDescription *TriggerDescription `json:",omitempty"`
+
+	// Extra is the unknown keys. See Manifest.Extra.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (e *Encoder) UnmarshalJSON(data []byte) error {
+	type alias Encoder
+	return unmarshalObject(data, (*alias)(e), &e.Extra)
+}
+
+func (e Encoder) MarshalJSON() ([]byte, error) {
+	type alias Encoder
+	return marshalObject(alias(e), e.Extra)
+}
+
+// TriggerDescription describes the behavior of the encoder for each input.
+type TriggerDescription struct {
+	LongTouch *string `json:",omitempty"`
+	Push      *string `json:",omitempty"`
+	Rotate    *string `json:",omitempty"`
+	Touch     *string `json:",omitempty"`
+}
+
+// Nodejs is the configuration of a plugin written in JavaScript.
+type Nodejs struct {
+	Version                string
+	Debug                  *string `json:",omitempty"`
+	GenerateProfilerOutput *bool   `json:",omitempty"`
+
+	// Extra is the unknown keys. See Manifest.Extra.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (n *Nodejs) UnmarshalJSON(data []byte) error {
+	type alias Nodejs
+	return unmarshalObject(data, (*alias)(n), &n.Extra)
+}
+
+func (n Nodejs) MarshalJSON() ([]byte, error) {
+	type alias Nodejs
+	return marshalObject(alias(n), n.Extra)
 }
 
 type Profile struct {
@@ -58,6 +212,19 @@ type Profile struct {
 	DeviceType                  DeviceType
 	ReadOnly                    *bool `json:",omitempty"`
 	DontAutoSwitchWhenInstalled *bool `json:",omitempty"`
+
+	// Extra is the unknown keys. See Manifest.Extra.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (p *Profile) UnmarshalJSON(data []byte) error {
+	type alias Profile
+	return unmarshalObject(data, (*alias)(p), &p.Extra)
+}
+
+func (p Profile) MarshalJSON() ([]byte, error) {
+	type alias Profile
+	return marshalObject(alias(p), p.Extra)
 }
 
 type DeviceType int
@@ -69,11 +236,26 @@ const (
 	DeviceTypeStreamDeckMobile DeviceType = 3
 	DeviceTypeCorsairGKeys     DeviceType = 4
 	DeviceTypeStreamDeckPanel  DeviceType = 5
+	DeviceTypeCorsairVoyager   DeviceType = 6
+	DeviceTypeStreamDeckPlus   DeviceType = 7
 )
 
 type OS struct {
 	Platform       Platform
 	MinimumVersion string
+
+	// Extra is the unknown keys. See Manifest.Extra.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (o *OS) UnmarshalJSON(data []byte) error {
+	type alias OS
+	return unmarshalObject(data, (*alias)(o), &o.Extra)
+}
+
+func (o OS) MarshalJSON() ([]byte, error) {
+	type alias OS
+	return marshalObject(alias(o), o.Extra)
 }
 
 type Platform string
@@ -85,6 +267,19 @@ const (
 
 type Software struct {
 	MinimumVersion string
+
+	// Extra is the unknown keys. See Manifest.Extra.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (s *Software) UnmarshalJSON(data []byte) error {
+	type alias Software
+	return unmarshalObject(data, (*alias)(s), &s.Extra)
+}
+
+func (s Software) MarshalJSON() ([]byte, error) {
+	type alias Software
+	return marshalObject(alias(s), s.Extra)
 }
 
 type ApplicationsToMonitor struct {
@@ -92,13 +287,23 @@ type ApplicationsToMonitor struct {
 	Windows []string
 }
 
+type applicationsToMonitor struct {
+	Mac     []string `json:"mac,omitempty"`
+	Windows []string `json:"windows,omitempty"`
+}
+
 func (a ApplicationsToMonitor) MarshalJSON() ([]byte, error) {
-	type obj struct {
-		Mac     []string `json:"mac"`
-		Windows []string `json:"windows"`
+	return json.Marshal(applicationsToMonitor(a))
+}
+
+func (a *ApplicationsToMonitor) UnmarshalJSON(data []byte) error {
+	var o applicationsToMonitor
+	err := json.Unmarshal(data, &o)
+	if err != nil {
+		return err
 	}
-	o := obj(a)
-	return json.Marshal(o)
+	*a = ApplicationsToMonitor(o)
+	return nil
 }
 
 func OptionalString(s string) *string {
@@ -0,0 +1,167 @@
+package manifest
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// compileSchema compiles the manifest schema described in testdata/schema/README.md.
+func compileSchema(t *testing.T) *jsonschema.Schema {
+	t.Helper()
+	s, err := jsonschema.Compile(filepath.Join("testdata", "schema", "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func validateSchema(t *testing.T, s *jsonschema.Schema, data []byte) {
+	t.Helper()
+	var v interface{}
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Validate(v)
+	if err != nil {
+		t.Errorf("%#v\n%s", err, data)
+	}
+}
+
+func TestSchema_Testdata(t *testing.T) {
+	s := compileSchema(t)
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			m, err := Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			validateSchema(t, s, data)
+		})
+	}
+}
+
+func TestSchema_AllFields(t *testing.T) {
+	m := &Manifest{
+		Actions: []Action{
+			{
+				Icon:                    "images/dial",
+				Name:                    "Dial",
+				PropertyInspectorPath:   OptionalString("pi/dial.html"),
+				SupportedInMultiActions: OptionalBool(false),
+				Tooltip:                 OptionalString("tooltip"),
+				UUID:                    "com.example.plugin.dial",
+				VisibleInActionsList:    OptionalBool(true),
+				DisableCaching:          OptionalBool(true),
+				DisableAutomaticStates:  OptionalBool(true),
+				UserTitleEnabled:        OptionalBool(false),
+				OnlyMultiActions:        OptionalBool(false),
+				Controllers:             []Controller{ControllerKeypad, ControllerEncoder},
+				Encoder: &Encoder{
+					Background: OptionalString("images/background"),
+					Icon:       OptionalString("images/encoder"),
+					Layout:     OptionalString("$B1"),
+					StackColor: OptionalString("#000000"),
+					TriggerDescription: &TriggerDescription{
+						LongTouch: OptionalString("reset"),
+						Push:      OptionalString("mute"),
+						Rotate:    OptionalString("volume"),
+						Touch:     OptionalString("mute"),
+					},
+				},
+				States: []State{
+					{
+						Image:                   "images/off",
+						MultiActionImage:        OptionalString("images/multi"),
+						Name:                    OptionalString("Off"),
+						Title:                   OptionalString("OFF"),
+						ShowTitle:               OptionalBool(true),
+						TitleColor:              OptionalString("#ffffff"),
+						TitleAlignment:          OptionalString("bottom"),
+						FontFamily:              OptionalString("Verdana"),
+						FontStyle:               OptionalString("Bold Italic"),
+						FontSize:                OptionalInt(12),
+						FontUnderline:           OptionalBool(false),
+						SupportedInMultiActions: OptionalBool(true),
+						PropertyInspectorPath:   OptionalString("pi/off.html"),
+					},
+					{Image: "images/on"},
+				},
+			},
+		},
+		Author:                "author",
+		Category:              OptionalString("Category"),
+		CategoryIcon:          OptionalString("images/category"),
+		CodePath:              "plugin",
+		CodePathMac:           OptionalString("plugin"),
+		CodePathWin:           OptionalString("plugin.exe"),
+		Description:           "description",
+		Icon:                  "images/icon",
+		Name:                  "Plugin",
+		Profiles:              []Profile{{Name: "Plus", DeviceType: DeviceTypeStreamDeckPlus, ReadOnly: OptionalBool(true), DontAutoSwitchWhenInstalled: OptionalBool(true)}},
+		PropertyInspectorPath: OptionalString("pi/index.html"),
+		DefaultWindowSize:     []int{500, 650},
+		URL:                   OptionalString("https://example.com"),
+		Version:               "1.0.0.1",
+		SDKVersion:            2,
+		OS: []OS{
+			{Platform: PlatformMac, MinimumVersion: "10.15"},
+			{Platform: PlatformWindows, MinimumVersion: "10"},
+		},
+		Software: Software{MinimumVersion: "6.0"},
+		ApplicationsToMonitor: &ApplicationsToMonitor{
+			Mac:     []string{"com.apple.Music"},
+			Windows: []string{"Spotify.exe"},
+		},
+		Nodejs: &Nodejs{Version: "20", Debug: OptionalString("enabled"), GenerateProfilerOutput: OptionalBool(false)},
+	}
+	if ps := m.Validate(); ps != nil {
+		t.Fatal(ps)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateSchema(t, compileSchema(t), data)
+}
+
+func TestSchema_Builder(t *testing.T) {
+	m, err := New("Plugin").
+		Author("author").
+		Icon("icon").
+		Binary("plugin").
+		Action("com.example.plugin.dial", func(a *ActionBuilder) {
+			a.Name("Dial").
+				UserTitleEnabled(false).
+				OnlyMultiActions(false).
+				Encoder(Encoder{Layout: OptionalString("$A0")}).
+				State("off", func(s *StateBuilder) {
+					s.Font("Verdana", "Bold", 10, true).
+						SupportedInMultiActions(true).
+						PropertyInspector("pi/off.html")
+				})
+		}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateSchema(t, compileSchema(t), data)
+}
@@ -0,0 +1,322 @@
+package manifest
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/morikuni/go-stream-deck-sdk/internal/fsutil"
+)
+
+// Problem is a violation of the manifest rules.
+type Problem struct {
+	// Path is the JSON path to the field, e.g. $.Actions[0].UUID.
+	Path    string
+	Message string
+}
+
+func (p *Problem) Error() string {
+	return p.Path + ": " + p.Message
+}
+
+// Problems is the list of problems found in a manifest.
+type Problems []*Problem
+
+func (ps Problems) Error() string {
+	msgs := make([]string, len(ps))
+	for i, p := range ps {
+		msgs[i] = p.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Err returns ps as an error, or nil if there are no problems.
+func (ps Problems) Err() error {
+	if len(ps) == 0 {
+		return nil
+	}
+	return ps
+}
+
+// SupportedSDKVersions is the versions of SDKVersion accepted by Validate.
+var SupportedSDKVersions = []int{2}
+
+var (
+	uuidPattern    = regexp.MustCompile(`^[a-z0-9-]+(\.[a-z0-9-]+)+$`)
+	versionPattern = regexp.MustCompile(`^\d+(\.\d+){1,3}$`)
+)
+
+type validator struct {
+	problems Problems
+}
+
+func (v *validator) add(path, format string, a ...interface{}) {
+	v.problems = append(v.problems, &Problem{Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+func (v *validator) required(path, value string) {
+	if value == "" {
+		v.add(path, "required")
+	}
+}
+
+// Validate checks the manifest with the rules in the manifest documentation.
+// It returns nil if the manifest is valid.
+func (m *Manifest) Validate() Problems {
+	var v validator
+
+	v.required("$.Author", m.Author)
+	v.required("$.CodePath", m.CodePath)
+	v.required("$.Description", m.Description)
+	v.required("$.Icon", m.Icon)
+	v.required("$.Name", m.Name)
+	v.required("$.Version", m.Version)
+	if m.Version != "" && !versionPattern.MatchString(m.Version) {
+		v.add("$.Version", "must be numbers separated by dots like 1.0.0: %q", m.Version)
+	}
+	if m.CategoryIcon != nil && m.Category == nil {
+		v.add("$.CategoryIcon", "Category is required for CategoryIcon")
+	}
+	if m.DefaultWindowSize != nil && len(m.DefaultWindowSize) != 2 {
+		v.add("$.DefaultWindowSize", "must be [width, height]")
+	}
+
+	supported := false
+	for _, sv := range SupportedSDKVersions {
+		supported = supported || m.SDKVersion == sv
+	}
+	if !supported {
+		v.add("$.SDKVersion", "unsupported version %d: supported versions are %v", m.SDKVersion, SupportedSDKVersions)
+	}
+
+	if len(m.Actions) == 0 {
+		v.add("$.Actions", "at least one action is required")
+	}
+	uuids := make(map[string]int)
+	for i, a := range m.Actions {
+		path := fmt.Sprintf("$.Actions[%d]", i)
+		v.validateAction(path, &a)
+		if j, ok := uuids[a.UUID]; ok && a.UUID != "" {
+			v.add(path+".UUID", "duplicated with $.Actions[%d].UUID: %q", j, a.UUID)
+		}
+		uuids[a.UUID] = i
+	}
+
+	if len(m.OS) == 0 {
+		v.add("$.OS", "at least one platform is required")
+	}
+	platforms := make(map[Platform]bool)
+	for i, o := range m.OS {
+		path := fmt.Sprintf("$.OS[%d]", i)
+		switch o.Platform {
+		case PlatformMac, PlatformWindows:
+		default:
+			v.add(path+".Platform", "must be %q or %q: %q", PlatformMac, PlatformWindows, o.Platform)
+		}
+		if platforms[o.Platform] {
+			v.add(path+".Platform", "duplicated platform: %q", o.Platform)
+		}
+		platforms[o.Platform] = true
+		v.required(path+".MinimumVersion", o.MinimumVersion)
+	}
+	if m.CodePathMac != nil && !platforms[PlatformMac] {
+		v.add("$.CodePathMac", "%q is not in OS", PlatformMac)
+	}
+	if m.CodePathWin != nil && !platforms[PlatformWindows] {
+		v.add("$.CodePathWin", "%q is not in OS", PlatformWindows)
+	}
+
+	v.required("$.Software.MinimumVersion", m.Software.MinimumVersion)
+	if m.Software.MinimumVersion != "" && !versionPattern.MatchString(m.Software.MinimumVersion) {
+		v.add("$.Software.MinimumVersion", "must be numbers separated by dots like 5.0: %q", m.Software.MinimumVersion)
+	}
+
+	if m.ApplicationsToMonitor != nil {
+		v.validateApplications("$.ApplicationsToMonitor.mac", m.ApplicationsToMonitor.Mac, PlatformMac, platforms)
+		v.validateApplications("$.ApplicationsToMonitor.windows", m.ApplicationsToMonitor.Windows, PlatformWindows, platforms)
+		if len(m.ApplicationsToMonitor.Mac) == 0 && len(m.ApplicationsToMonitor.Windows) == 0 {
+			v.add("$.ApplicationsToMonitor", "at least one application is required")
+		}
+	}
+
+	if m.Nodejs != nil {
+		v.required("$.Nodejs.Version", m.Nodejs.Version)
+	}
+
+	for i, p := range m.Profiles {
+		path := fmt.Sprintf("$.Profiles[%d]", i)
+		v.required(path+".Name", p.Name)
+		if p.DeviceType < DeviceTypeStreamDeck || p.DeviceType > DeviceTypeStreamDeckPlus {
+			v.add(path+".DeviceType", "unknown device type: %d", p.DeviceType)
+		}
+	}
+
+	return v.problems
+}
+
+func (v *validator) validateApplications(path string, apps []string, platform Platform, platforms map[Platform]bool) {
+	if len(apps) > 0 && !platforms[platform] {
+		v.add(path, "%q is not in OS", platform)
+	}
+	seen := make(map[string]bool, len(apps))
+	for i, app := range apps {
+		v.required(fmt.Sprintf("%s[%d]", path, i), app)
+		if seen[app] && app != "" {
+			v.add(fmt.Sprintf("%s[%d]", path, i), "duplicated application: %q", app)
+		}
+		seen[app] = true
+	}
+}
+
+func (v *validator) validateAction(path string, a *Action) {
+	v.required(path+".Icon", a.Icon)
+	v.required(path+".Name", a.Name)
+	v.required(path+".UUID", a.UUID)
+	if a.UUID != "" && !uuidPattern.MatchString(a.UUID) {
+		v.add(path+".UUID", "must be a reverse-DNS format with lowercase alphanumeric characters, hyphens and periods: %q", a.UUID)
+	}
+
+	encoder := false
+	if a.Controllers != nil && len(a.Controllers) == 0 {
+		v.add(path+".Controllers", "at least one controller is required")
+	}
+	seen := make(map[Controller]bool, len(a.Controllers))
+	for i, c := range a.Controllers {
+		cpath := fmt.Sprintf("%s.Controllers[%d]", path, i)
+		switch c {
+		case ControllerKeypad:
+		case ControllerEncoder:
+			encoder = true
+		default:
+			v.add(cpath, "must be %q or %q: %q", ControllerKeypad, ControllerEncoder, c)
+		}
+		if seen[c] {
+			v.add(cpath, "duplicated controller: %q", c)
+		}
+		seen[c] = true
+	}
+	if a.Encoder != nil && !encoder {
+		v.add(path+".Encoder", "%q is not in Controllers", ControllerEncoder)
+	}
+
+	if len(a.States) < 1 || len(a.States) > 2 {
+		v.add(path+".States", "must have 1 or 2 states but has %d", len(a.States))
+	}
+	for i, s := range a.States {
+		spath := fmt.Sprintf("%s.States[%d]", path, i)
+		v.required(spath+".Image", s.Image)
+		if s.TitleAlignment != nil {
+			switch *s.TitleAlignment {
+			case "top", "middle", "bottom":
+			default:
+				v.add(spath+".TitleAlignment", "must be top, middle or bottom: %q", *s.TitleAlignment)
+			}
+		}
+		if s.FontStyle != nil {
+			switch *s.FontStyle {
+			case "Regular", "Bold", "Italic", "Bold Italic":
+			default:
+				v.add(spath+".FontStyle", "must be Regular, Bold, Italic or Bold Italic: %q", *s.FontStyle)
+			}
+		}
+		if s.FontSize != nil && *s.FontSize <= 0 {
+			v.add(spath+".FontSize", "must be positive: %d", *s.FontSize)
+		}
+	}
+}
+
+// ValidateDir checks the manifest in addition to Validate, with the files
+// in the plugin directory (.sdPlugin) that the manifest refers to.
+// The localization files in the directory are checked as well, and the
+// paths of their problems are prefixed with the file name like de.json:$.Name.
+func (m *Manifest) ValidateDir(dir string) Problems {
+	v := validator{problems: m.Validate()}
+
+	v.image(dir, "$.Icon", m.Icon)
+	if m.CategoryIcon != nil {
+		v.image(dir, "$.CategoryIcon", *m.CategoryIcon)
+	}
+	v.file(dir, "$.CodePath", m.CodePath)
+	if m.CodePathMac != nil {
+		v.file(dir, "$.CodePathMac", *m.CodePathMac)
+	}
+	if m.CodePathWin != nil {
+		v.file(dir, "$.CodePathWin", *m.CodePathWin)
+	}
+	if m.PropertyInspectorPath != nil {
+		v.file(dir, "$.PropertyInspectorPath", *m.PropertyInspectorPath)
+	}
+	for i, p := range m.Profiles {
+		v.file(dir, fmt.Sprintf("$.Profiles[%d].Name", i), p.Name+".streamDeckProfile")
+	}
+
+	for i, a := range m.Actions {
+		path := fmt.Sprintf("$.Actions[%d]", i)
+		v.image(dir, path+".Icon", a.Icon)
+		if a.PropertyInspectorPath != nil {
+			v.file(dir, path+".PropertyInspectorPath", *a.PropertyInspectorPath)
+		}
+		for j, s := range a.States {
+			spath := fmt.Sprintf("%s.States[%d]", path, j)
+			v.image(dir, spath+".Image", s.Image)
+			if s.MultiActionImage != nil {
+				v.image(dir, spath+".MultiActionImage", *s.MultiActionImage)
+			}
+			if s.PropertyInspectorPath != nil {
+				v.file(dir, spath+".PropertyInspectorPath", *s.PropertyInspectorPath)
+			}
+		}
+		if a.Encoder != nil && a.Encoder.Icon != nil {
+			v.image(dir, path+".Encoder.Icon", *a.Encoder.Icon)
+		}
+		if a.Encoder != nil && a.Encoder.Background != nil {
+			v.image(dir, path+".Encoder.background", *a.Encoder.Background)
+		}
+	}
+
+	for _, lang := range SupportedLanguages {
+		file := LocalizationPath(dir, lang)
+		if !fsutil.IsFile(file) {
+			continue
+		}
+		name := filepath.Base(file)
+		l, err := LoadLocalization(file)
+		if err != nil {
+			v.add(name, "%v", err)
+			continue
+		}
+		for _, p := range m.ValidateLocalization(lang, l) {
+			v.add(name+":"+p.Path, "%s", p.Message)
+		}
+	}
+
+	return v.problems
+}
+
+// ImageFiles returns the candidate files of an image referred without
+// the extension in the manifest.
+func ImageFiles(path string) []string {
+	return []string{path + ".png", path + ".svg", path + ".gif", path}
+}
+
+func (v *validator) image(dir, path, image string) {
+	if image == "" {
+		return
+	}
+	for _, f := range ImageFiles(image) {
+		if fsutil.IsFile(filepath.Join(dir, filepath.FromSlash(f))) {
+			return
+		}
+	}
+	v.add(path, "image not found: %s.png or %s.svg", image, image)
+}
+
+func (v *validator) file(dir, path, file string) {
+	if file == "" {
+		return
+	}
+	if !fsutil.IsFile(filepath.Join(dir, filepath.FromSlash(file))) {
+		v.add(path, "file not found: %s", file)
+	}
+}
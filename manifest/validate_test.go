@@ -0,0 +1,161 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func validManifest() *Manifest {
+	return &Manifest{
+		Actions: []Action{
+			{
+				Icon:   "images/action",
+				Name:   "Action",
+				States: []State{{Image: "images/state"}},
+				UUID:   "com.example.plugin.action",
+			},
+		},
+		Author:      "author",
+		CodePath:    "plugin",
+		CodePathMac: OptionalString("plugin"),
+		Description: "description",
+		Icon:        "images/icon",
+		Name:        "Plugin",
+		Version:     "1.0.0",
+		SDKVersion:  2,
+		OS: []OS{
+			{Platform: PlatformMac, MinimumVersion: "10.11"},
+		},
+		Software: Software{MinimumVersion: "5.0"},
+	}
+}
+
+func TestManifest_Validate(t *testing.T) {
+	for name, tt := range map[string]struct {
+		modify func(m *Manifest)
+
+		want Problems
+	}{
+		"valid": {
+			func(m *Manifest) {},
+			nil,
+		},
+		"required": {
+			func(m *Manifest) {
+				m.Author = ""
+				m.Actions[0].Name = ""
+				m.Actions[0].States[0].Image = ""
+			},
+			Problems{
+				{Path: "$.Author", Message: "required"},
+				{Path: "$.Actions[0].Name", Message: "required"},
+				{Path: "$.Actions[0].States[0].Image", Message: "required"},
+			},
+		},
+		"uuid": {
+			func(m *Manifest) {
+				m.Actions[0].UUID = "Action"
+				m.Actions = append(m.Actions, m.Actions[0])
+			},
+			Problems{
+				{Path: "$.Actions[0].UUID", Message: `must be a reverse-DNS format with lowercase alphanumeric characters, hyphens and periods: "Action"`},
+				{Path: "$.Actions[1].UUID", Message: `must be a reverse-DNS format with lowercase alphanumeric characters, hyphens and periods: "Action"`},
+				{Path: "$.Actions[1].UUID", Message: `duplicated with $.Actions[0].UUID: "Action"`},
+			},
+		},
+		"states": {
+			func(m *Manifest) {
+				m.Actions[0].States = append(m.Actions[0].States, State{Image: "a"}, State{Image: "b", TitleAlignment: OptionalString("left")})
+			},
+			Problems{
+				{Path: "$.Actions[0].States", Message: "must have 1 or 2 states but has 3"},
+				{Path: "$.Actions[0].States[2].TitleAlignment", Message: `must be top, middle or bottom: "left"`},
+			},
+		},
+		"sdk version": {
+			func(m *Manifest) {
+				m.SDKVersion = 1
+			},
+			Problems{
+				{Path: "$.SDKVersion", Message: "unsupported version 1: supported versions are [2]"},
+			},
+		},
+		"os": {
+			func(m *Manifest) {
+				m.OS = nil
+			},
+			Problems{
+				{Path: "$.OS", Message: "at least one platform is required"},
+				{Path: "$.CodePathMac", Message: `"mac" is not in OS`},
+			},
+		},
+		"controllers": {
+			func(m *Manifest) {
+				m.Actions[0].Controllers = []Controller{ControllerKeypad, "Dial", ControllerKeypad}
+				m.Actions[0].Encoder = &Encoder{Layout: OptionalString("$A0")}
+			},
+			Problems{
+				{Path: "$.Actions[0].Controllers[1]", Message: `must be "Keypad" or "Encoder": "Dial"`},
+				{Path: "$.Actions[0].Controllers[2]", Message: `duplicated controller: "Keypad"`},
+				{Path: "$.Actions[0].Encoder", Message: `"Encoder" is not in Controllers`},
+			},
+		},
+		"applications to monitor": {
+			func(m *Manifest) {
+				m.ApplicationsToMonitor = &ApplicationsToMonitor{
+					Mac:     []string{"com.apple.Music", "", "com.apple.Music"},
+					Windows: []string{"Spotify.exe"},
+				}
+			},
+			Problems{
+				{Path: "$.ApplicationsToMonitor.mac[1]", Message: "required"},
+				{Path: "$.ApplicationsToMonitor.mac[2]", Message: `duplicated application: "com.apple.Music"`},
+				{Path: "$.ApplicationsToMonitor.windows", Message: `"windows" is not in OS`},
+			},
+		},
+		"nodejs and profiles": {
+			func(m *Manifest) {
+				m.Nodejs = &Nodejs{}
+				m.Profiles = []Profile{{Name: "Plus", DeviceType: DeviceTypeStreamDeckPlus}, {Name: "Unknown", DeviceType: 8}}
+			},
+			Problems{
+				{Path: "$.Nodejs.Version", Message: "required"},
+				{Path: "$.Profiles[1].DeviceType", Message: "unknown device type: 8"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			m := validManifest()
+			tt.modify(m)
+
+			got := m.Validate()
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Fatalf("(+want, -got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestManifest_ValidateDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"plugin", "images/icon.png", "images/action.svg"} {
+		path := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := validManifest().ValidateDir(dir)
+	want := Problems{
+		{Path: "$.Actions[0].States[0].Image", Message: "image not found: images/state.png or images/state.svg"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("(+want, -got): %s", diff)
+	}
+}
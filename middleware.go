@@ -0,0 +1,136 @@
+package streamdeck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"time"
+)
+
+// Middleware wraps a Handler to add a cross-cutting behavior.
+type Middleware func(Handler) Handler
+
+// Chain wraps h with the middlewares.
+// The first middleware is the outermost one, which sees an event first.
+func Chain(h Handler, mws ...Middleware) Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// LogEvents logs every event and the error returned by the handler.
+func LogEvents(sdk *SDK) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, ev Event) error {
+			err := next.Handle(ctx, ev)
+			if err != nil {
+				sdk.Logf("go-stream-deck-sdk: %s: error: %v", describeEvent(ev), err)
+			} else {
+				sdk.Logf("go-stream-deck-sdk: %s: ok", describeEvent(ev))
+			}
+			return err
+		})
+	}
+}
+
+// Timing calls record with the duration taken to handle each event.
+func Timing(record func(ev Event, d time.Duration, err error)) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, ev Event) error {
+			start := time.Now()
+			err := next.Handle(ctx, ev)
+			record(ev, time.Since(start), err)
+			return err
+		})
+	}
+}
+
+// ErrPanic is wrapped by the error returned by Recover for a recovered panic.
+// SDK.Receive keeps receiving events on the error because the panic has
+// already been reported.
+var ErrPanic = errors.New("panic in handler")
+
+// Recover recovers a panic in the handler and reports it in the same way
+// as WithPanicRecovery and WithCrashReporter. The panic is turned into
+// an error wrapping ErrPanic so that the outer middlewares see it.
+func Recover(sdk *SDK) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, ev Event) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					sdk.reportCrash(ctx, &CrashReport{
+						Event:     ev,
+						Recovered: r,
+						Stack:     debug.Stack(),
+					})
+					err = fmt.Errorf("%w: %v", ErrPanic, r)
+				}
+			}()
+
+			return next.Handle(ctx, ev)
+		})
+	}
+}
+
+// Timeout cancels the context passed to the handler after d.
+func Timeout(d time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, ev Event) error {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			return next.Handle(ctx, ev)
+		})
+	}
+}
+
+// FilterEvents passes only the events of the same types as the given events
+// to the handler, e.g. FilterEvents(&KeyDown{}, &KeyUp{}).
+func FilterEvents(events ...Event) Middleware {
+	types := make(map[reflect.Type]bool, len(events))
+	for _, ev := range events {
+		types[reflect.TypeOf(ev)] = true
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, ev Event) error {
+			if !types[reflect.TypeOf(ev)] {
+				return nil
+			}
+			return next.Handle(ctx, ev)
+		})
+	}
+}
+
+// FilterActions passes only the events for the given actions to the handler.
+// The events that are not related to an action are dropped.
+func FilterActions(actions ...ActionID) Middleware {
+	ids := make(map[ActionID]bool, len(actions))
+	for _, id := range actions {
+		ids[id] = true
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, ev Event) error {
+			id, ok := actionIDOf(ev)
+			if !ok || !ids[id] {
+				return nil
+			}
+			return next.Handle(ctx, ev)
+		})
+	}
+}
+
+func describeEvent(ev Event) string {
+	s := fmt.Sprintf("%T", ev)
+	if id, ok := actionIDOf(ev); ok {
+		s += " action=" + string(id)
+	}
+	if id, ok := instanceIDOf(ev); ok {
+		s += " context=" + string(id)
+	}
+	return s
+}
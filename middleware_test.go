@@ -0,0 +1,157 @@
+package streamdeck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChain(t *testing.T) {
+	var calls []string
+	mw := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return HandlerFunc(func(ctx context.Context, ev Event) error {
+				calls = append(calls, name)
+				return next.Handle(ctx, ev)
+			})
+		}
+	}
+
+	h := Chain(HandlerFunc(func(ctx context.Context, ev Event) error {
+		calls = append(calls, "handler")
+		return nil
+	}), mw("first"), mw("second"))
+
+	noError(t, h.Handle(context.Background(), &KeyDown{}))
+	equal(t, calls, []string{"first", "second", "handler"})
+}
+
+func TestFilter(t *testing.T) {
+	for name, tt := range map[string]struct {
+		mw Middleware
+
+		want []bool
+	}{
+		"events": {
+			FilterEvents(&KeyDown{}, &SystemDidWakeUp{}),
+			[]bool{true, false, true},
+		},
+		"actions": {
+			FilterActions("action1"),
+			[]bool{true, false, false},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var got []bool
+			called := false
+			h := Chain(HandlerFunc(func(ctx context.Context, ev Event) error {
+				called = true
+				return nil
+			}), tt.mw)
+
+			for _, ev := range []Event{
+				&KeyDown{Action: "action1"},
+				&KeyUp{Action: "action2"},
+				&SystemDidWakeUp{},
+			} {
+				called = false
+				noError(t, h.Handle(context.Background(), ev))
+				got = append(got, called)
+			}
+
+			equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	h := Chain(HandlerFunc(func(ctx context.Context, ev Event) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), Timeout(time.Millisecond))
+
+	err := h.Handle(context.Background(), &KeyDown{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("unexpected error:", err)
+	}
+}
+
+func TestLogEvents(t *testing.T) {
+	sdk, srv := newTestSDK(t)
+	errFailed := errors.New("failed")
+	h := Chain(HandlerFunc(func(ctx context.Context, ev Event) error {
+		if _, ok := ev.(*KeyUp); ok {
+			return errFailed
+		}
+		return nil
+	}), LogEvents(sdk))
+
+	noError(t, h.Handle(context.Background(), &KeyDown{Action: "action1", Context: "context"}))
+	err := h.Handle(context.Background(), &KeyUp{Action: "action1", Context: "context"})
+	if !errors.Is(err, errFailed) {
+		t.Fatal("unexpected error:", err)
+	}
+
+	for _, want := range []string{
+		"go-stream-deck-sdk: *streamdeck.KeyDown action=action1 context=context: ok",
+		"go-stream-deck-sdk: *streamdeck.KeyUp action=action1 context=context: error: failed",
+	} {
+		cmd := nextCommand(t, srv)
+		equal(t, cmd.Event, "logMessage")
+		var log LogMessage
+		noError(t, json.Unmarshal(cmd.Payload, &log))
+		equal(t, log.Message, want)
+	}
+}
+
+func TestTiming(t *testing.T) {
+	type record struct {
+		ev  Event
+		d   time.Duration
+		err error
+	}
+	var got []record
+	errFailed := errors.New("failed")
+	ev := &KeyDown{}
+	h := Chain(HandlerFunc(func(ctx context.Context, ev Event) error {
+		time.Sleep(time.Millisecond)
+		return errFailed
+	}), Timing(func(ev Event, d time.Duration, err error) {
+		got = append(got, record{ev, d, err})
+	}))
+
+	err := h.Handle(context.Background(), ev)
+	if !errors.Is(err, errFailed) {
+		t.Fatal("unexpected error:", err)
+	}
+	equal(t, len(got), 1)
+	if got[0].ev != ev || !errors.Is(got[0].err, errFailed) {
+		t.Fatalf("unexpected record: %+v", got[0])
+	}
+	if got[0].d < time.Millisecond {
+		t.Fatal("the duration must include the handler:", got[0].d)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	var reports []*CrashReport
+	sdk, srv := newTestSDK(t, WithCrashReporter(func(ctx context.Context, report *CrashReport) {
+		reports = append(reports, report)
+	}))
+	h := Chain(HandlerFunc(func(ctx context.Context, ev Event) error {
+		panic("boom")
+	}), Recover(sdk))
+
+	err := h.Handle(context.Background(), &KeyDown{Context: "context"})
+	if !errors.Is(err, ErrPanic) {
+		t.Fatal("want ErrPanic but got", err)
+	}
+	equal(t, err.Error(), "panic in handler: boom")
+
+	equal(t, nextCommand(t, srv).Event, "logMessage")
+	equal(t, nextCommand(t, srv).Event, "showAlert")
+	equal(t, len(reports), 1)
+	equal(t, reports[0].Recovered, "boom")
+}
@@ -0,0 +1,117 @@
+package pi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	streamdeck "github.com/morikuni/go-stream-deck-sdk"
+)
+
+// Registration is the arguments passed to connectElgatoStreamDeckSocket.
+type Registration struct {
+	Port          string
+	UUID          string
+	RegisterEvent string
+	Info          json.RawMessage
+	ActionInfo    ActionInfo
+}
+
+// ActionInfo is the information of the action instance the property inspector is for.
+type ActionInfo struct {
+	Action  streamdeck.ActionID   `json:"action"`
+	Context streamdeck.InstanceID `json:"context"`
+	Device  streamdeck.DeviceID   `json:"device"`
+	Payload struct {
+		Settings    json.RawMessage        `json:"settings"`
+		Coordinates streamdeck.Coordinates `json:"coordinates"`
+	} `json:"payload"`
+}
+
+type transport interface {
+	Send(data []byte) error
+	Close() error
+}
+
+// Client communicates with the Stream Deck application from a property inspector.
+type Client struct {
+	t   transport
+	reg *Registration
+
+	mu      sync.Mutex
+	onEvent func(ev Event)
+}
+
+func newClient(t transport, reg *Registration) *Client {
+	return &Client{t: t, reg: reg}
+}
+
+// Registration returns the arguments the client is connected with.
+func (c *Client) Registration() *Registration {
+	return c.reg
+}
+
+// OnEvent sets the function called with an event received from the application.
+func (c *Client) OnEvent(f func(ev Event)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onEvent = f
+}
+
+// Send sends the command on behalf of the property inspector.
+func (c *Client) Send(cmd Command) error {
+	p, err := newCommandPayload(cmd, c.reg.UUID, c.reg.ActionInfo.Action)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal a command: %w: %v", err, cmd)
+	}
+
+	return c.t.Send(data)
+}
+
+// SetSettings saves the settings of the action instance.
+func (c *Client) SetSettings(settings interface{}) error {
+	return c.Send(&SetSettings{Settings: settings})
+}
+
+// GetSettings requests the settings of the action instance,
+// which are delivered by DidReceiveSettings.
+func (c *Client) GetSettings() error {
+	return c.Send(&GetSettings{})
+}
+
+// SendToPlugin sends the payload to the plugin, which receives streamdeck.SendToPlugin.
+func (c *Client) SendToPlugin(payload interface{}) error {
+	return c.Send(&SendToPlugin{Payload: payload})
+}
+
+func (c *Client) Close() error {
+	return c.t.Close()
+}
+
+func (c *Client) receive(data []byte) error {
+	var ep eventPayload
+	err := json.Unmarshal(data, &ep)
+	if err != nil {
+		return fmt.Errorf("failed to decode an event: %w", err)
+	}
+
+	ev, err := ep.Typed()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	onEvent := c.onEvent
+	c.mu.Unlock()
+
+	if onEvent != nil {
+		onEvent(ev)
+	}
+	return nil
+}
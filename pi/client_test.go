@@ -0,0 +1,91 @@
+package pi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeTransport struct {
+	sent []json.RawMessage
+}
+
+func (t *fakeTransport) Send(data []byte) error {
+	t.sent = append(t.sent, data)
+	return nil
+}
+
+func (t *fakeTransport) Close() error {
+	return nil
+}
+
+func newTestClient() (*Client, *fakeTransport) {
+	t := &fakeTransport{}
+	reg := &Registration{UUID: "piUUID"}
+	reg.ActionInfo.Action = "com.example.action"
+	return newClient(t, reg), t
+}
+
+func TestClient_Send(t *testing.T) {
+	c, tr := newTestClient()
+
+	for _, send := range []func() error{
+		func() error { return c.SetSettings(map[string]int{"count": 1}) },
+		c.GetSettings,
+		func() error { return c.SendToPlugin(map[string]string{"key": "value"}) },
+	} {
+		if err := send(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, want := range []string{
+		`{"event": "setSettings", "context": "piUUID", "payload": {"count": 1}}`,
+		`{"event": "getSettings", "context": "piUUID"}`,
+		`{"event": "sendToPlugin", "action": "com.example.action", "context": "piUUID", "payload": {"key": "value"}}`,
+	} {
+		var got, w interface{}
+		if err := json.Unmarshal(tr.sent[i], &got); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal([]byte(want), &w); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, w); diff != "" {
+			t.Fatalf("(+want, -got): %s", diff)
+		}
+	}
+}
+
+func TestClient_Receive(t *testing.T) {
+	c, _ := newTestClient()
+
+	var events []Event
+	c.OnEvent(func(ev Event) {
+		events = append(events, ev)
+	})
+
+	for _, msg := range []string{
+		`{"event": "didReceiveSettings", "action": "com.example.action", "context": "context", "payload": {"settings": {"count": 1}}}`,
+		`{"event": "sendToPropertyInspector", "action": "com.example.action", "context": "context", "payload": {"key": "value"}}`,
+	} {
+		if err := c.receive([]byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(events) != 2 {
+		t.Fatal("unexpected events:", events)
+	}
+	if ev, ok := events[0].(*DidReceiveSettings); !ok || string(ev.Settings) != `{"count": 1}` {
+		t.Fatalf("unexpected event: %#v", events[0])
+	}
+	if ev, ok := events[1].(*SendToPropertyInspector); !ok || string(ev.Payload) != `{"key": "value"}` {
+		t.Fatalf("unexpected event: %#v", events[1])
+	}
+
+	if err := c.receive([]byte(`{"event": "unknown"}`)); err == nil {
+		t.Fatal("error expected for an unknown event")
+	}
+}
@@ -0,0 +1,149 @@
+package pi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	streamdeck "github.com/morikuni/go-stream-deck-sdk"
+	"github.com/morikuni/go-stream-deck-sdk/internal/protocol"
+)
+
+// Command interface is to restrict command object that can
+// implement the interface. The commands that are implementing
+// the interface is enumerated below the definition in the source code.
+// The commands are sent by a property inspector, which differ from
+// the ones sent by a plugin.
+type Command interface {
+	commandMark()
+	event() string
+}
+
+var _ = []Command{
+	(*SetSettings)(nil),
+	(*GetSettings)(nil),
+	(*SetGlobalSettings)(nil),
+	(*GetGlobalSettings)(nil),
+	(*OpenURL)(nil),
+	(*LogMessage)(nil),
+	(*SendToPlugin)(nil),
+}
+
+type noPayloadCommand struct{}
+
+func (*noPayloadCommand) commandMark() {}
+
+type payloadCommand struct{}
+
+func (*payloadCommand) commandMark() {}
+
+func (*payloadCommand) hasPayload() {}
+
+type commandPayload = protocol.CommandPayload
+
+// newCommandPayload builds the message of the command. All commands are sent
+// with the UUID of the property inspector as the context.
+func newCommandPayload(cmd Command, piUUID string, action streamdeck.ActionID) (*commandPayload, error) {
+	p := &commandPayload{
+		Event:   cmd.event(),
+		Context: piUUID,
+	}
+
+	if _, ok := cmd.(interface{ hasPayload() }); ok {
+		payload, err := json.Marshal(cmd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal a command: %w: %v", err, cmd)
+		}
+		p.Payload = payload
+	}
+	if _, ok := cmd.(interface{ hasAction() }); ok {
+		p.Action = string(action)
+	}
+
+	return p, nil
+}
+
+type SetSettings struct {
+	payloadCommand
+
+	// Settings is the value marshaled into the settings object.
+	Settings interface{} `json:"-"`
+}
+
+func (*SetSettings) event() string {
+	return "setSettings"
+}
+
+// MarshalJSON marshals the settings as the payload.
+func (cmd *SetSettings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cmd.Settings)
+}
+
+type GetSettings struct {
+	noPayloadCommand
+}
+
+func (*GetSettings) event() string {
+	return "getSettings"
+}
+
+type SetGlobalSettings struct {
+	payloadCommand
+
+	// Settings is the value marshaled into the settings object.
+	Settings interface{} `json:"-"`
+}
+
+func (*SetGlobalSettings) event() string {
+	return "setGlobalSettings"
+}
+
+// MarshalJSON marshals the settings as the payload.
+func (cmd *SetGlobalSettings) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cmd.Settings)
+}
+
+type GetGlobalSettings struct {
+	noPayloadCommand
+}
+
+func (*GetGlobalSettings) event() string {
+	return "getGlobalSettings"
+}
+
+type OpenURL struct {
+	payloadCommand
+
+	URL string `json:"url"`
+}
+
+func (*OpenURL) event() string {
+	return "openUrl"
+}
+
+type LogMessage struct {
+	payloadCommand
+
+	Message string `json:"message"`
+}
+
+func (*LogMessage) event() string {
+	return "logMessage"
+}
+
+type SendToPlugin struct {
+	payloadCommand
+
+	// Payload is the value marshaled into the payload.
+	Payload interface{} `json:"-"`
+}
+
+func (*SendToPlugin) event() string {
+	return "sendToPlugin"
+}
+
+func (*SendToPlugin) hasAction() {}
+
+// MarshalJSON marshals the payload as is.
+func (cmd *SendToPlugin) MarshalJSON() ([]byte, error) {
+	return json.Marshal(cmd.Payload)
+}
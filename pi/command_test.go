@@ -0,0 +1,114 @@
+package pi
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNewCommandPayload(t *testing.T) {
+	toJSON := func(i interface{}) json.RawMessage {
+		b, err := json.Marshal(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	for _, tt := range []struct {
+		cmd Command
+
+		want *commandPayload
+	}{
+		{
+			cmd: &SetSettings{
+				Settings: map[string]string{
+					"key": "value",
+				},
+			},
+			want: &commandPayload{
+				Event:   "setSettings",
+				Context: "piUUID",
+				Payload: toJSON(map[string]string{
+					"key": "value",
+				}),
+			},
+		},
+		{
+			cmd: &GetSettings{},
+			want: &commandPayload{
+				Event:   "getSettings",
+				Context: "piUUID",
+			},
+		},
+		{
+			cmd: &SetGlobalSettings{
+				Settings: map[string]string{
+					"key": "value",
+				},
+			},
+			want: &commandPayload{
+				Event:   "setGlobalSettings",
+				Context: "piUUID",
+				Payload: toJSON(map[string]string{
+					"key": "value",
+				}),
+			},
+		},
+		{
+			cmd: &GetGlobalSettings{},
+			want: &commandPayload{
+				Event:   "getGlobalSettings",
+				Context: "piUUID",
+			},
+		},
+		{
+			cmd: &OpenURL{
+				URL: "url",
+			},
+			want: &commandPayload{
+				Event:   "openUrl",
+				Context: "piUUID",
+				Payload: toJSON(map[string]string{
+					"url": "url",
+				}),
+			},
+		},
+		{
+			cmd: &LogMessage{
+				Message: "message",
+			},
+			want: &commandPayload{
+				Event:   "logMessage",
+				Context: "piUUID",
+				Payload: toJSON(map[string]string{
+					"message": "message",
+				}),
+			},
+		},
+		{
+			cmd: &SendToPlugin{
+				Payload: map[string]string{
+					"key": "value",
+				},
+			},
+			want: &commandPayload{
+				Event:   "sendToPlugin",
+				Context: "piUUID",
+				Action:  "com.elgato.example.action1", // set only for sendToPlugin.
+				Payload: toJSON(map[string]string{
+					"key": "value",
+				}),
+			},
+		},
+	} {
+		t.Run(fmt.Sprintf("%T", tt.cmd), func(t *testing.T) {
+			cp, err := newCommandPayload(tt.cmd, "piUUID", "com.elgato.example.action1")
+			noError(t, err)
+			equal(t, cp, tt.want, cmpopts.IgnoreFields(commandPayload{}, "Payload"))
+			equalJSON(t, cp.Payload, tt.want.Payload)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+//go:build js && wasm
+
+package pi
+
+import (
+	"encoding/json"
+	"errors"
+	"syscall/js"
+)
+
+// Connect waits for the Stream Deck application to call connectElgatoStreamDeckSocket,
+// and connects to the application with the arguments.
+// It must be called before the page is loaded because the application calls
+// the function after loading the page.
+func Connect() (*Client, error) {
+	regCh := make(chan *Registration, 1)
+	errCh := make(chan error, 1)
+
+	var connect js.Func
+	connect = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		defer connect.Release()
+
+		if len(args) < 5 {
+			errCh <- errors.New("connectElgatoStreamDeckSocket is called with insufficient arguments")
+			return nil
+		}
+
+		reg := &Registration{
+			Port:          args[0].String(),
+			UUID:          args[1].String(),
+			RegisterEvent: args[2].String(),
+			Info:          json.RawMessage(args[3].String()),
+		}
+		err := json.Unmarshal([]byte(args[4].String()), &reg.ActionInfo)
+		if err != nil {
+			errCh <- err
+			return nil
+		}
+		regCh <- reg
+		return nil
+	})
+	js.Global().Set("connectElgatoStreamDeckSocket", connect)
+
+	var reg *Registration
+	select {
+	case reg = <-regCh:
+	case err := <-errCh:
+		return nil, err
+	}
+
+	return dial(reg)
+}
+
+func dial(reg *Registration) (*Client, error) {
+	ws := js.Global().Get("WebSocket").New("ws://127.0.0.1:" + reg.Port)
+	t := &wsTransport{ws: ws}
+	c := newClient(t, reg)
+
+	opened := make(chan error, 1)
+	t.onOpen = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		data, err := json.Marshal(map[string]string{
+			"event": reg.RegisterEvent,
+			"uuid":  reg.UUID,
+		})
+		if err == nil {
+			err = t.Send(data)
+		}
+		select {
+		case opened <- err:
+		default:
+		}
+		return nil
+	})
+	t.onError = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		select {
+		case opened <- errors.New("failed to connect to the Stream Deck application"):
+		default:
+		}
+		return nil
+	})
+	t.onMessage = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		data := args[0].Get("data").String()
+		// handle the message in another goroutine because a handler might block.
+		go func() {
+			err := c.receive([]byte(data))
+			if err != nil {
+				js.Global().Get("console").Call("error", "go-stream-deck-sdk:", err.Error())
+			}
+		}()
+		return nil
+	})
+	ws.Set("onopen", t.onOpen)
+	ws.Set("onerror", t.onError)
+	ws.Set("onmessage", t.onMessage)
+
+	err := <-opened
+	if err != nil {
+		_ = t.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+type wsTransport struct {
+	ws js.Value
+
+	onOpen    js.Func
+	onError   js.Func
+	onMessage js.Func
+}
+
+func (t *wsTransport) Send(data []byte) error {
+	if t.ws.Get("readyState").Int() != 1 {
+		return errors.New("websocket is not open")
+	}
+	t.ws.Call("send", string(data))
+	return nil
+}
+
+func (t *wsTransport) Close() error {
+	t.ws.Call("close")
+	t.onOpen.Release()
+	t.onError.Release()
+	t.onMessage.Release()
+	return nil
+}
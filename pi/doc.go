@@ -0,0 +1,7 @@
+// Package pi is a client library for property inspectors written in Go.
+// It is built with GOOS=js GOARCH=wasm and runs in the property inspector
+// page loaded by the Stream Deck application.
+//
+// The events received and the commands sent by a property inspector are
+// defined in this package because they differ from the ones of a plugin.
+package pi
@@ -0,0 +1,84 @@
+package pi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	streamdeck "github.com/morikuni/go-stream-deck-sdk"
+	"github.com/morikuni/go-stream-deck-sdk/internal/protocol"
+)
+
+// Event interface is to restrict event object that can
+// implement the interface. The events that are implementing
+// the interface is enumerated below the definition in the source code.
+// The events are received by a property inspector, which differ from
+// the ones received by a plugin.
+type Event interface {
+	eventMark()
+}
+
+var _ = []Event{
+	(*DidReceiveSettings)(nil),
+	(*DidReceiveGlobalSettings)(nil),
+	(*SendToPropertyInspector)(nil),
+}
+
+type eventMarkImpl struct{}
+
+func (*eventMarkImpl) eventMark() {}
+
+type eventPayload struct {
+	protocol.EventPayload
+}
+
+func (ep eventPayload) Typed() (Event, error) {
+	var e = func() Event {
+		switch ep.Event {
+		case "didReceiveSettings":
+			return &DidReceiveSettings{}
+		case "didReceiveGlobalSettings":
+			return &DidReceiveGlobalSettings{}
+		case "sendToPropertyInspector":
+			return &SendToPropertyInspector{}
+		default:
+			return nil
+		}
+	}()
+	if e == nil {
+		return nil, fmt.Errorf("unknown event: %s", ep.Event)
+	}
+
+	err := ep.Bind(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+type DidReceiveSettings struct {
+	eventMarkImpl
+
+	Action  streamdeck.ActionID   `json:"action"`
+	Context streamdeck.InstanceID `json:"context"`
+	Device  streamdeck.DeviceID   `json:"device"`
+
+	Settings        json.RawMessage        `json:"settings"`
+	Coordinates     streamdeck.Coordinates `json:"coordinates"`
+	IsInMultiAction bool                   `json:"isInMultiAction"`
+}
+
+type DidReceiveGlobalSettings struct {
+	eventMarkImpl
+
+	Settings json.RawMessage `json:"settings"`
+}
+
+type SendToPropertyInspector struct {
+	eventMarkImpl
+
+	Action  streamdeck.ActionID   `json:"action"`
+	Context streamdeck.InstanceID `json:"context"`
+
+	Payload json.RawMessage `json:"payload"`
+}
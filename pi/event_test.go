@@ -0,0 +1,128 @@
+package pi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	streamdeck "github.com/morikuni/go-stream-deck-sdk"
+)
+
+func TestEventPayload_Typed(t *testing.T) {
+	for name, tt := range map[string]struct {
+		json string
+
+		want Event
+	}{
+		"didReceiveSettings": {
+			didReceiveSettingsJSON,
+			&DidReceiveSettings{
+				Action:   "com.elgato.example.action1",
+				Context:  "context",
+				Device:   "device",
+				Settings: json.RawMessage(`{"key": "value"}`),
+				Coordinates: streamdeck.Coordinates{
+					Row:    1,
+					Column: 3,
+				},
+				IsInMultiAction: true,
+			},
+		},
+		"didReceiveGlobalSettings": {
+			didReceiveGlobalSettingsJSON,
+			&DidReceiveGlobalSettings{
+				Settings: json.RawMessage(`{"key": "value"}`),
+			},
+		},
+		"sendToPropertyInspector": {
+			sendToPropertyInspectorJSON,
+			&SendToPropertyInspector{
+				Action:  "com.elgato.example.action1",
+				Context: "context",
+				Payload: json.RawMessage(`{"key": "value"}`),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var ep eventPayload
+			err := json.Unmarshal([]byte(tt.json), &ep)
+			noError(t, err)
+
+			tp, err := ep.Typed()
+			noError(t, err)
+
+			equal(t, tp, tt.want, ignoreUnexported(tt.want))
+		})
+	}
+}
+
+var didReceiveSettingsJSON = `{
+    "action": "com.elgato.example.action1",
+    "event": "didReceiveSettings",
+    "context": "context",
+    "device": "device",
+    "payload": {
+        "settings": {"key": "value"},
+        "coordinates": {
+            "column": 3, 
+            "row": 1
+        },
+        "isInMultiAction": true
+    }
+}`
+
+var didReceiveGlobalSettingsJSON = `{
+    "event": "didReceiveGlobalSettings",
+    "payload": {
+        "settings": {"key": "value"}
+    }
+}`
+
+var sendToPropertyInspectorJSON = `{
+    "action": "com.elgato.example.action1",
+    "event": "sendToPropertyInspector",
+    "context": "context",
+    "payload": {"key": "value"}
+}`
+
+func noError(tb testing.TB, err error) {
+	tb.Helper()
+
+	if err != nil {
+		tb.Fatal("unexpected error:", err)
+	}
+}
+
+func equal(tb testing.TB, got, want interface{}, opts ...cmp.Option) {
+	tb.Helper()
+
+	if diff := cmp.Diff(got, want, opts...); diff != "" {
+		tb.Fatalf("(+want, -got): %s", diff)
+	}
+}
+
+func equalJSON(tb testing.TB, got, want []byte, opts ...cmp.Option) {
+	tb.Helper()
+
+	if len(got) == 0 || len(want) == 0 {
+		equal(tb, got, want, opts...)
+		return
+	}
+
+	var gotV, wantV interface{}
+	err := json.Unmarshal(got, &gotV)
+	noError(tb, err)
+	err = json.Unmarshal(want, &wantV)
+	noError(tb, err)
+
+	if diff := cmp.Diff(gotV, wantV, opts...); diff != "" {
+		tb.Fatalf("(+want, -got): %s", diff)
+	}
+}
+
+func ignoreUnexported(v interface{}) cmp.Option {
+	return cmpopts.IgnoreUnexported(reflect.Indirect(reflect.ValueOf(v)).Interface())
+}
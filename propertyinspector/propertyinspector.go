@@ -0,0 +1,284 @@
+// Package propertyinspector generates a property inspector from a Go settings struct.
+//
+// The fields of the struct are rendered with the sdpi styles in the order of
+// definition, and configured by the sdpi tag.
+//
+//	type Settings struct {
+//		Interval int    `json:"interval" default:"5" sdpi:"label=Interval,widget=range,min=1,max=60"`
+//		Mode     string `json:"mode" sdpi:"label=Mode,options=clock:Clock|timer:Timer"`
+//	}
+//
+// The keys of the sdpi tag are:
+//
+//	label     the label of the field. Default is the field name.
+//	widget    text, password, textarea, number, range, checkbox, select or color.
+//	          Default is checkbox for bool, number for numbers and text for the others.
+//	options   the options of select separated by |. A label can follow a value after :.
+//	min, max  the range of number and range. The min and max rules in the validate tag are used by default.
+//	step      the step of number and range.
+//
+// time.Duration is not supported because the settings would hold nanoseconds
+// entered as a number. Use a number of a unit such as seconds instead.
+//
+// The default tag is used as the initial value as well as streamdeck.Settings.
+// The generated property inspector keeps the other keys in the settings when it saves them.
+package propertyinspector
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+//go:embed propertyinspector.html.tmpl
+var htmlTemplate string
+
+var tmpl = template.Must(template.New("propertyinspector").Parse(htmlTemplate))
+
+type Option option
+
+// WithTitle sets the title of the HTML.
+func WithTitle(title string) Option {
+	return func(cfg *config) {
+		cfg.title = title
+	}
+}
+
+// WithStylesheet links the stylesheet of the sdpi styles at the path relative
+// to the HTML. The SDK does not ship sdpi.css, so no stylesheet is linked by default.
+func WithStylesheet(path string) Option {
+	return func(cfg *config) {
+		cfg.stylesheet = path
+	}
+}
+
+type option func(*config)
+
+type config struct {
+	title      string
+	stylesheet string
+}
+
+// Field is a field in the property inspector.
+type Field struct {
+	// Key is the path to the field in the settings joined by dots.
+	Key     string
+	Label   string
+	Widget  string
+	Type    string
+	Default string
+	Options []FieldOption
+	Min     string
+	Max     string
+	Step    string
+}
+
+type FieldOption struct {
+	Value string
+	Label string
+}
+
+// Fields returns the fields of the settings struct.
+func Fields(settings interface{}) ([]*Field, error) {
+	rt := reflect.TypeOf(settings)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	if rt == nil || rt.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("settings must be a struct: %T", settings)
+	}
+
+	return fields(rt, "")
+}
+
+func fields(rt reflect.Type, prefix string) ([]*Field, error) {
+	var fs []*Field
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := sf.Name
+		if tag, ok := sf.Tag.Lookup("json"); ok {
+			n := strings.Split(tag, ",")[0]
+			if n == "-" {
+				continue
+			}
+			if n != "" {
+				name = n
+			} else if sf.Anonymous {
+				name = ""
+			}
+		} else if sf.Anonymous {
+			name = ""
+		}
+
+		if sf.Type.Kind() == reflect.Struct {
+			p := prefix
+			if name != "" {
+				p += name + "."
+			}
+			nested, err := fields(sf.Type, p)
+			if err != nil {
+				return nil, err
+			}
+			fs = append(fs, nested...)
+			continue
+		}
+
+		f, err := newField(sf, prefix+name)
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func newField(sf reflect.StructField, key string) (*Field, error) {
+	f := &Field{
+		Key:     key,
+		Label:   sf.Name,
+		Default: sf.Tag.Get("default"),
+	}
+
+	if sf.Type == durationType {
+		return nil, fmt.Errorf("unsupported field type %s of %s: use a number of a unit such as seconds", sf.Type, sf.Name)
+	}
+
+	switch sf.Type.Kind() {
+	case reflect.Bool:
+		f.Type, f.Widget = "boolean", "checkbox"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		f.Type, f.Widget = "number", "number"
+	case reflect.String:
+		f.Type, f.Widget = "string", "text"
+	default:
+		return nil, fmt.Errorf("unsupported field type %s of %s", sf.Type, sf.Name)
+	}
+
+	for _, rule := range strings.Split(sf.Tag.Get("validate"), ",") {
+		switch {
+		case strings.HasPrefix(rule, "min="):
+			f.Min = strings.TrimPrefix(rule, "min=")
+		case strings.HasPrefix(rule, "max="):
+			f.Max = strings.TrimPrefix(rule, "max=")
+		}
+	}
+
+	tag := sf.Tag.Get("sdpi")
+	if tag == "" {
+		return f, nil
+	}
+	for _, kv := range strings.Split(tag, ",") {
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid sdpi tag of %s: %q", sf.Name, kv)
+		}
+		k, v := kv[:i], kv[i+1:]
+		switch k {
+		case "label":
+			f.Label = v
+		case "widget":
+			f.Widget = v
+		case "options":
+			for _, o := range strings.Split(v, "|") {
+				value, label := o, o
+				if j := strings.Index(o, ":"); j >= 0 {
+					value, label = o[:j], o[j+1:]
+				}
+				f.Options = append(f.Options, FieldOption{Value: value, Label: label})
+			}
+		case "min":
+			f.Min = v
+		case "max":
+			f.Max = v
+		case "step":
+			f.Step = v
+		default:
+			return nil, fmt.Errorf("unknown key in sdpi tag of %s: %q", sf.Name, k)
+		}
+	}
+
+	if len(f.Options) > 0 && f.Widget == "text" {
+		f.Widget = "select"
+	}
+	switch f.Widget {
+	case "text", "password", "textarea", "number", "range", "checkbox", "select", "color":
+	default:
+		return nil, fmt.Errorf("unknown widget of %s: %q", sf.Name, f.Widget)
+	}
+	if f.Widget == "select" && len(f.Options) == 0 {
+		return nil, fmt.Errorf("select of %s has no options", sf.Name)
+	}
+
+	return f, nil
+}
+
+// Generate writes the HTML of the property inspector for the settings struct.
+func Generate(w io.Writer, settings interface{}, opts ...Option) error {
+	cfg := config{
+		title: "Property Inspector",
+	}
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	fs, err := Fields(settings)
+	if err != nil {
+		return err
+	}
+
+	err = tmpl.Execute(w, map[string]interface{}{
+		"Title":      cfg.title,
+		"Stylesheet": cfg.stylesheet,
+		"Fields":     fs,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render property inspector: %w", err)
+	}
+
+	return nil
+}
+
+// WriteFile generates the property inspector for the action at
+// propertyinspector/<UUID>.html in the plugin directory, and sets the path to
+// PropertyInspectorPath of the action. A stylesheet given by WithStylesheet
+// is relative to the propertyinspector directory, e.g. ../sdpi.css.
+func WriteFile(pluginDir string, action *manifest.Action, settings interface{}, opts ...Option) error {
+	path := filepath.ToSlash(filepath.Join("propertyinspector", action.UUID+".html"))
+
+	opts = append([]Option{WithTitle(action.Name)}, opts...)
+	var buf bytes.Buffer
+	err := Generate(&buf, settings, opts...)
+	if err != nil {
+		return err
+	}
+
+	dst := filepath.Join(pluginDir, filepath.FromSlash(path))
+	err = os.MkdirAll(filepath.Dir(dst), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	err = os.WriteFile(dst, buf.Bytes(), 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write property inspector: %w", err)
+	}
+
+	action.PropertyInspectorPath = manifest.OptionalString(path)
+	return nil
+}
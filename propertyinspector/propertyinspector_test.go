@@ -0,0 +1,128 @@
+package propertyinspector
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/morikuni/go-stream-deck-sdk/manifest"
+)
+
+type testSettings struct {
+	Name     string `json:"name" sdpi:"label=Name"`
+	Interval int    `json:"interval" default:"5" validate:"min=1,max=60" sdpi:"label=Interval,widget=range"`
+	Mode     string `json:"mode" default:"clock" sdpi:"label=Mode,options=clock:Clock|timer:Timer"`
+	Enabled  bool   `json:"enabled"`
+	Server   struct {
+		Port int `json:"port" sdpi:"step=1"`
+	} `json:"server"`
+	Ignored string `json:"-"`
+}
+
+func TestFields(t *testing.T) {
+	got, err := Fields(&testSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*Field{
+		{Key: "name", Label: "Name", Widget: "text", Type: "string"},
+		{Key: "interval", Label: "Interval", Widget: "range", Type: "number", Default: "5", Min: "1", Max: "60"},
+		{Key: "mode", Label: "Mode", Widget: "select", Type: "string", Default: "clock", Options: []FieldOption{
+			{Value: "clock", Label: "Clock"},
+			{Value: "timer", Label: "Timer"},
+		}},
+		{Key: "enabled", Label: "Enabled", Widget: "checkbox", Type: "boolean"},
+		{Key: "server.port", Label: "Port", Widget: "number", Type: "number", Step: "1"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("(+want, -got): %s", diff)
+	}
+}
+
+func TestFields_Invalid(t *testing.T) {
+	for name, settings := range map[string]interface{}{
+		"not struct": 1,
+		"unknown widget": struct {
+			A string `sdpi:"widget=slider"`
+		}{},
+		"unknown key": struct {
+			A string `sdpi:"color=red"`
+		}{},
+		"select without options": struct {
+			A string `sdpi:"widget=select"`
+		}{},
+		"unsupported type": struct {
+			A []string
+		}{},
+		"duration": struct {
+			A time.Duration
+		}{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Fields(settings)
+			if err == nil {
+				t.Fatal("error expected")
+			}
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	action := &manifest.Action{UUID: "com.example.action", Name: "Action"}
+
+	err := WriteFile(dir, action, testSettings{}, WithStylesheet("../sdpi.css"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if action.PropertyInspectorPath == nil || *action.PropertyInspectorPath != "propertyinspector/com.example.action.html" {
+		t.Fatal("unexpected path:", action.PropertyInspectorPath)
+	}
+
+	html, err := os.ReadFile(filepath.Join(dir, "propertyinspector", "com.example.action.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	err = Generate(&buf, testSettings{}, WithTitle("Action"), WithStylesheet("../sdpi.css"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(html, buf.Bytes()) {
+		t.Fatal("the file must be the same as Generate")
+	}
+
+	for _, want := range []string{
+		`<title>Action</title>`,
+		`<link rel="stylesheet" href="../sdpi.css">`,
+		`<input type="range" id="interval" data-key="interval" data-type="number" data-default="5" min="1" max="60">`,
+		`<option value="timer">Timer</option>`,
+		`<input class="sdpi-item-value" type="checkbox" id="enabled" data-key="enabled" data-type="boolean" data-default="">`,
+		`data-key="server.port"`,
+		`function connectElgatoStreamDeckSocket(`,
+	} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("%q is not found", want)
+		}
+	}
+}
+
+func TestGenerate_NoStylesheet(t *testing.T) {
+	var buf bytes.Buffer
+	err := Generate(&buf, testSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "<link") {
+		t.Fatal("no stylesheet must be linked by default:", buf.String())
+	}
+}
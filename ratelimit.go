@@ -0,0 +1,153 @@
+package streamdeck
+
+import (
+	"sync"
+	"time"
+)
+
+// sendQueue holds commands waiting to be sent under the rate limit.
+// High priority commands are sent first and never coalesced.
+// Low priority commands with the same coalesce key are merged into the latest one.
+type sendQueue struct {
+	mu     sync.Mutex
+	high   []*commandPayload
+	low    []*queuedCommand
+	keys   map[string]*queuedCommand
+	closed bool
+	notify chan struct{}
+}
+
+type queuedCommand struct {
+	payload *commandPayload
+}
+
+func newSendQueue() *sendQueue {
+	return &sendQueue{
+		keys:   make(map[string]*queuedCommand),
+		notify: make(chan struct{}, 1),
+	}
+}
+
+func (q *sendQueue) push(p *commandPayload, highPriority bool, coalesceKey string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false
+	}
+
+	switch {
+	case highPriority:
+		q.high = append(q.high, p)
+	case coalesceKey != "" && q.keys[coalesceKey] != nil:
+		q.keys[coalesceKey].payload = p
+	default:
+		qc := &queuedCommand{p}
+		q.low = append(q.low, qc)
+		if coalesceKey != "" {
+			q.keys[coalesceKey] = qc
+		}
+	}
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// wait blocks until a command is queued or the queue is closed.
+// It returns false if the queue is closed and empty.
+func (q *sendQueue) wait() bool {
+	for {
+		q.mu.Lock()
+		n, closed := len(q.high)+len(q.low), q.closed
+		q.mu.Unlock()
+
+		if n > 0 {
+			return true
+		}
+		if closed {
+			return false
+		}
+		<-q.notify
+	}
+}
+
+func (q *sendQueue) pop() (*commandPayload, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.high) > 0 {
+		p := q.high[0]
+		q.high = q.high[1:]
+		return p, true
+	}
+	if len(q.low) > 0 {
+		qc := q.low[0]
+		q.low = q.low[1:]
+		for k, v := range q.keys {
+			if v == qc {
+				delete(q.keys, k)
+			}
+		}
+		return qc.payload, true
+	}
+	return nil, false
+}
+
+func (q *sendQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// rateLimiter is a token bucket.
+type rateLimiter struct {
+	interval time.Duration
+	burst    int
+
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(perSecond, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		interval: time.Second / time.Duration(perSecond),
+		burst:    burst,
+		tokens:   float64(burst),
+		last:     time.Now(),
+	}
+}
+
+func (l *rateLimiter) wait(stop <-chan struct{}) {
+	now := time.Now()
+	l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		d := time.Duration((1 - l.tokens) * float64(l.interval))
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-stop:
+			// flush without waiting on close.
+			timer.Stop()
+		}
+		l.tokens = 1
+		l.last = time.Now()
+	}
+
+	l.tokens--
+}
@@ -0,0 +1,135 @@
+package streamdeck
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morikuni/go-stream-deck-sdk/streamdecktest"
+)
+
+func TestSendQueue(t *testing.T) {
+	q := newSendQueue()
+	for _, c := range []struct {
+		event string
+		high  bool
+		key   string
+	}{
+		{"setImage1", false, "a"},
+		{"logMessage", true, ""},
+		{"openUrl", false, ""},
+		{"setImage2", false, "a"},
+		{"setImage3", false, "b"},
+		{"showAlert", true, ""},
+	} {
+		equal(t, q.push(&commandPayload{Event: c.event}, c.high, c.key), true)
+	}
+
+	var got []string
+	for {
+		p, ok := q.pop()
+		if !ok {
+			break
+		}
+		got = append(got, p.Event)
+	}
+	equal(t, got, []string{"logMessage", "showAlert", "setImage2", "openUrl", "setImage3"})
+
+	// the key can be used again after the command is sent.
+	q.push(&commandPayload{Event: "setImage4"}, false, "a")
+	p, _ := q.pop()
+	equal(t, p.Event, "setImage4")
+
+	q.close()
+	equal(t, q.push(&commandPayload{Event: "setImage5"}, false, "a"), false)
+	equal(t, q.wait(), false)
+}
+
+func TestConn_RateLimit(t *testing.T) {
+	srv := streamdecktest.NewServer()
+	defer srv.Close()
+
+	conn, err := Dial(WithPort(srv.Port()), WithPluginUUID("pluginUUID"), WithRegisterEvent("registerPlugin"), WithRateLimit(20, 1))
+	noError(t, err)
+	_, err = srv.Registration(time.Second)
+	noError(t, err)
+
+	images := []Image{"0", "1", "2", "3", "4"}
+	for _, img := range images {
+		noError(t, conn.Send(&SetImage{Context: "context", Image: img}))
+	}
+	noError(t, conn.Send(&ShowAlert{Context: "context"}))
+	noError(t, conn.Close())
+
+	var got []Image
+	alert := false
+	for !alert || len(got) == 0 || got[len(got)-1] != "4" {
+		cmd, err := srv.NextCommand(time.Second)
+		noError(t, err)
+
+		switch cmd.Event {
+		case "showAlert":
+			alert = true
+		case "setImage":
+			var si SetImage
+			noError(t, json.Unmarshal(cmd.Payload, &si))
+			got = append(got, si.Image)
+		}
+	}
+
+	if len(got) >= len(images) {
+		t.Fatal("pending images must be coalesced:", got)
+	}
+}
+
+func TestConn_RateLimit_LogFallback(t *testing.T) {
+	srv := streamdecktest.NewServer()
+	defer srv.Close()
+
+	conn, err := Dial(WithPort(srv.Port()), WithPluginUUID("pluginUUID"), WithRegisterEvent("registerPlugin"), WithRateLimit(20, 1))
+	noError(t, err)
+	_, err = srv.Registration(time.Second)
+	noError(t, err)
+
+	path := filepath.Join(t.TempDir(), "plugin.log")
+	l, err := NewFileLogger(path)
+	noError(t, err)
+	sdk := NewSDK(conn, WithFileLogger(l))
+
+	// the send fails in the background after the websocket is closed.
+	noError(t, conn.conn.Close())
+	sdk.Log("hello")
+	_ = conn.Close()
+	noError(t, l.Close())
+
+	got, err := os.ReadFile(path)
+	noError(t, err)
+	if !strings.HasSuffix(string(got), " hello\n") {
+		t.Fatalf("the log must be written to the file: %q", got)
+	}
+}
+
+func TestConn_RateLimit_RenderCache(t *testing.T) {
+	srv := streamdecktest.NewServer()
+	defer srv.Close()
+
+	conn, err := Dial(WithPort(srv.Port()), WithPluginUUID("pluginUUID"), WithRegisterEvent("registerPlugin"), WithRateLimit(20, 1))
+	noError(t, err)
+	_, err = srv.Registration(time.Second)
+	noError(t, err)
+
+	sdk := NewSDK(conn, WithRenderCache())
+
+	// the send fails in the background after the websocket is closed.
+	noError(t, conn.conn.Close())
+	noError(t, sdk.SetTitle("context", "a", TargetBoth, 0))
+	_ = conn.Close()
+
+	key := renderKey{context: "context", event: "setTitle", target: TargetBoth}
+	if sdk.renderCache.has(key, "a") {
+		t.Fatal("the title failed to be sent must not be cached")
+	}
+}
@@ -0,0 +1,29 @@
+package streamdeck
+
+// RegistrationInfo is the information passed to the plugin with -info
+// on launch.
+type RegistrationInfo struct {
+	Application      ApplicationInfo    `json:"application"`
+	Plugin           PluginInfo         `json:"plugin"`
+	DevicePixelRatio int                `json:"devicePixelRatio"`
+	Colors           map[string]string  `json:"colors"`
+	Devices          []RegisteredDevice `json:"devices"`
+}
+
+type ApplicationInfo struct {
+	Font            string `json:"font"`
+	Language        string `json:"language"`
+	Platform        string `json:"platform"`
+	PlatformVersion string `json:"platformVersion"`
+	Version         string `json:"version"`
+}
+
+type PluginInfo struct {
+	UUID    string `json:"uuid"`
+	Version string `json:"version"`
+}
+
+type RegisteredDevice struct {
+	ID DeviceID `json:"id"`
+	DeviceInfo
+}
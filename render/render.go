@@ -0,0 +1,115 @@
+// Package render composes key images from layers such as a background,
+// text, a progress bar and a badge, to be passed to SDK.SetImage.
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/draw"
+
+	streamdeck "github.com/morikuni/go-stream-deck-sdk"
+	"github.com/morikuni/go-stream-deck-sdk/internal/imageutil"
+)
+
+// Layer draws a part of a key face. Layers are drawn from bottom to top.
+type Layer interface {
+	Draw(dst draw.Image)
+}
+
+// LayerFunc is a function implementing Layer.
+type LayerFunc func(dst draw.Image)
+
+func (f LayerFunc) Draw(dst draw.Image) {
+	f(dst)
+}
+
+// Render draws the layers on a transparent image of the size.
+func Render(size streamdeck.ImageSize, layers ...Layer) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, size.Width, size.Height))
+	for _, l := range layers {
+		l.Draw(dst)
+	}
+	return dst
+}
+
+// Compose draws the layers and encodes the result into an Image.
+func Compose(size streamdeck.ImageSize, layers ...Layer) (streamdeck.Image, error) {
+	return streamdeck.NewImageFromImage(Render(size, layers...), size)
+}
+
+// Background fills the whole image with the color.
+func Background(c color.Color) Layer {
+	return LayerFunc(func(dst draw.Image) {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(c), image.Point{}, draw.Over)
+	})
+}
+
+// BackgroundImage scales the image to cover the whole image keeping its
+// aspect ratio. The overflowing edges are cropped evenly.
+func BackgroundImage(img image.Image) Layer {
+	return LayerFunc(func(dst draw.Image) {
+		r := dst.Bounds()
+		src := img.Bounds()
+		crop := imageutil.FitRect(r.Size(), src.Size()).Add(src.Min)
+		draw.CatmullRom.Scale(dst, r, img, crop, draw.Over, nil)
+	})
+}
+
+// Icon draws the image scaled into the rectangle.
+func Icon(img image.Image, r image.Rectangle) Layer {
+	return LayerFunc(func(dst draw.Image) {
+		scaleInto(dst, r, img)
+	})
+}
+
+// Color parses a color in the form of #rgb, #rrggbb or #rrggbbaa
+// as used in TitleParameters. It returns false if s is malformed.
+func Color(s string) (color.Color, bool) {
+	if len(s) == 0 || s[0] != '#' {
+		return nil, false
+	}
+	s = s[1:]
+
+	var digits []uint8
+	for i := 0; i < len(s); i++ {
+		d, ok := hexDigit(s[i])
+		if !ok {
+			return nil, false
+		}
+		digits = append(digits, d)
+	}
+
+	switch len(digits) {
+	case 3:
+		return color.NRGBA{digits[0] * 0x11, digits[1] * 0x11, digits[2] * 0x11, 0xff}, true
+	case 6:
+		return color.NRGBA{digits[0]<<4 | digits[1], digits[2]<<4 | digits[3], digits[4]<<4 | digits[5], 0xff}, true
+	case 8:
+		return color.NRGBA{digits[0]<<4 | digits[1], digits[2]<<4 | digits[3], digits[4]<<4 | digits[5], digits[6]<<4 | digits[7]}, true
+	default:
+		return nil, false
+	}
+}
+
+func hexDigit(c byte) (uint8, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	default:
+		return 0, false
+	}
+}
+
+// scale returns v scaled from the 72px key to the image.
+func scale(dst draw.Image, v float64) float64 {
+	return v * float64(dst.Bounds().Dy()) / float64(streamdeck.ImageSizeKey.Height)
+}
+
+func scaleInto(dst draw.Image, r image.Rectangle, img image.Image) {
+	draw.CatmullRom.Scale(dst, r, img, img.Bounds(), draw.Over, nil)
+}
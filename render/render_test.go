@@ -0,0 +1,187 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	streamdeck "github.com/morikuni/go-stream-deck-sdk"
+)
+
+var (
+	black = color.NRGBA{0, 0, 0, 0xff}
+	red   = color.NRGBA{0xff, 0, 0, 0xff}
+	green = color.NRGBA{0, 0xff, 0, 0xff}
+	blue  = color.NRGBA{0, 0, 0xff, 0xff}
+)
+
+func TestRender(t *testing.T) {
+	for name, tt := range map[string]struct {
+		layers []Layer
+
+		want map[image.Point]color.NRGBA
+	}{
+		"background": {
+			[]Layer{Background(red)},
+			map[image.Point]color.NRGBA{
+				{0, 0}:   red,
+				{71, 71}: red,
+			},
+		},
+		"background image": {
+			// the sides of the wide image are cropped instead of squashed.
+			[]Layer{BackgroundImage(stripes(144, 72, red, green, blue))},
+			map[image.Point]color.NRGBA{
+				{3, 36}:  red,
+				{18, 36}: green,
+				{36, 36}: green,
+				{54, 36}: green,
+				{68, 36}: blue,
+			},
+		},
+		"progress bar": {
+			[]Layer{Background(black), ProgressBar(0.5, green, blue)},
+			map[image.Point]color.NRGBA{
+				{10, 63}: green,
+				{60, 63}: blue,
+				{36, 36}: black,
+			},
+		},
+		"progress ring": {
+			[]Layer{Background(black), ProgressRing(0.25, green, blue)},
+			map[image.Point]color.NRGBA{
+				{50, 10}: green, // top right quarter
+				{10, 50}: blue,
+				{36, 36}: black,
+			},
+		},
+		"badge": {
+			[]Layer{Background(black), Badge(3, red, color.White)},
+			map[image.Point]color.NRGBA{
+				{60, 5}:  red,
+				{10, 60}: black,
+			},
+		},
+		"zero badge": {
+			[]Layer{Background(black), Badge(0, red, color.White)},
+			map[image.Point]color.NRGBA{
+				{60, 5}: black,
+			},
+		},
+		"sparkline": {
+			[]Layer{Background(black), Sparkline([]float64{0, 1}, green)},
+			map[image.Point]color.NRGBA{
+				{36, 50}: green,
+				{6, 36}:  black,
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			img := Render(streamdeck.ImageSizeKey, tt.layers...)
+			for p, want := range tt.want {
+				got := img.NRGBAAt(p.X, p.Y)
+				if diff := cmp.Diff(got, want); diff != "" {
+					t.Errorf("pixel at %v (+want, -got): %s", p, diff)
+				}
+			}
+		})
+	}
+}
+
+// stripes returns an image of vertical stripes of the same width.
+func stripes(w, h int, cs ...color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.SetNRGBA(x, y, cs[x*len(cs)/w])
+		}
+	}
+	return img
+}
+
+func TestText(t *testing.T) {
+	for _, alignment := range []streamdeck.Alignment{
+		streamdeck.AlignmentTop,
+		streamdeck.AlignmentMiddle,
+		streamdeck.AlignmentBottom,
+	} {
+		t.Run(string(alignment), func(t *testing.T) {
+			img := Render(streamdeck.ImageSizeKey, Text("WWW", TextStyle{
+				Size:      16,
+				Color:     red,
+				Alignment: alignment,
+			}))
+
+			var rows []int
+			for y := 0; y < 72; y++ {
+				for x := 0; x < 72; x++ {
+					if img.NRGBAAt(x, y).A != 0 {
+						rows = append(rows, y)
+						break
+					}
+				}
+			}
+			if len(rows) == 0 {
+				t.Fatal("no text is drawn")
+			}
+
+			top, bottom := rows[0], rows[len(rows)-1]
+			switch alignment {
+			case streamdeck.AlignmentTop:
+				if top > 24 {
+					t.Fatal("text must be at the top:", top)
+				}
+			case streamdeck.AlignmentBottom:
+				if bottom < 48 {
+					t.Fatal("text must be at the bottom:", bottom)
+				}
+			default:
+				if top < 24 || bottom > 48 {
+					t.Fatal("text must be at the middle:", top, bottom)
+				}
+			}
+		})
+	}
+}
+
+func TestTextStyleFromTitleParameters(t *testing.T) {
+	got := TextStyleFromTitleParameters(streamdeck.TitleParameters{
+		FontFamily:     "Arial",
+		FontSize:       12,
+		FontStyle:      "Bold Italic",
+		FontUnderline:  true,
+		ShowTitle:      true,
+		TitleAlignment: streamdeck.AlignmentBottom,
+		TitleColor:     "#ff0000",
+	})
+
+	want := TextStyle{
+		Size:      12,
+		Color:     red,
+		Alignment: streamdeck.AlignmentBottom,
+		Bold:      true,
+		Italic:    true,
+		Underline: true,
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Fatalf("(+want, -got): %s", diff)
+	}
+}
+
+func TestColor(t *testing.T) {
+	for s, want := range map[string]color.Color{
+		"#f00":      red,
+		"#0000ff":   blue,
+		"#00ff0080": color.NRGBA{0, 0xff, 0, 0x80},
+		"ff0000":    nil,
+		"#ggg":      nil,
+		"#12345":    nil,
+	} {
+		got, ok := Color(s)
+		if ok != (want != nil) || got != want {
+			t.Errorf("%s: want %v, got %v", s, want, got)
+		}
+	}
+}
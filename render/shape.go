@@ -0,0 +1,167 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+
+	"golang.org/x/image/draw"
+)
+
+// ProgressBar draws a horizontal bar at the bottom showing value in [0, 1].
+// The track is drawn behind the bar unless it is nil.
+func ProgressBar(value float64, bar, track color.Color) Layer {
+	value = clamp(value)
+	return LayerFunc(func(dst draw.Image) {
+		b := dst.Bounds()
+		margin, height := scale(dst, 6), scale(dst, 6)
+		x0, x1 := float64(b.Min.X)+margin, float64(b.Max.X)-margin
+		y1 := float64(b.Max.Y) - margin
+		y0 := y1 - height
+
+		inBar := func(x, y float64) bool {
+			return x0 <= x && x <= x1 && y0 <= y && y <= y1
+		}
+		if track != nil {
+			fill(dst, inBar, track)
+		}
+		fill(dst, func(x, y float64) bool {
+			return inBar(x, y) && x <= x0+(x1-x0)*value
+		}, bar)
+	})
+}
+
+// ProgressRing draws a ring showing value in [0, 1] clockwise from the top.
+// The track is drawn behind the ring unless it is nil.
+func ProgressRing(value float64, ring, track color.Color) Layer {
+	value = clamp(value)
+	return LayerFunc(func(dst draw.Image) {
+		b := dst.Bounds()
+		cx, cy := float64(b.Min.X+b.Max.X)/2, float64(b.Min.Y+b.Max.Y)/2
+		outer := math.Min(float64(b.Dx()), float64(b.Dy()))/2 - scale(dst, 4)
+		inner := outer - scale(dst, 6)
+
+		inRing := func(x, y float64) bool {
+			d := math.Hypot(x-cx, y-cy)
+			return inner <= d && d <= outer
+		}
+		if track != nil {
+			fill(dst, inRing, track)
+		}
+		fill(dst, func(x, y float64) bool {
+			if !inRing(x, y) {
+				return false
+			}
+			// angle from the top, clockwise.
+			a := math.Atan2(x-cx, cy-y)
+			if a < 0 {
+				a += 2 * math.Pi
+			}
+			return a <= value*2*math.Pi
+		}, ring)
+	})
+}
+
+// Badge draws the count in a circle at the top right corner.
+// Nothing is drawn if count is not positive.
+func Badge(count int, bg, fg color.Color) Layer {
+	return LayerFunc(func(dst draw.Image) {
+		if count <= 0 {
+			return
+		}
+
+		label := strconv.Itoa(count)
+		if count > 99 {
+			label = "99+"
+		}
+
+		b := dst.Bounds()
+		r := scale(dst, 11)
+		cx, cy := float64(b.Max.X)-r-scale(dst, 2), float64(b.Min.Y)+r+scale(dst, 2)
+		fill(dst, func(x, y float64) bool {
+			return math.Hypot(x-cx, y-cy) <= r
+		}, bg)
+
+		area := image.Rect(int(cx-r), int(cy-r), int(math.Ceil(cx+r)), int(math.Ceil(cy+r)))
+		drawText(dst, area, label, TextStyle{
+			Size:  12,
+			Color: fg,
+			Bold:  true,
+		})
+	})
+}
+
+// Sparkline draws a line chart of the values in the lower half of the image.
+func Sparkline(values []float64, c color.Color) Layer {
+	return LayerFunc(func(dst draw.Image) {
+		if len(values) < 2 {
+			return
+		}
+
+		lo, hi := values[0], values[0]
+		for _, v := range values {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+		if hi == lo {
+			hi = lo + 1
+		}
+
+		b := dst.Bounds()
+		margin := scale(dst, 6)
+		x0, x1 := float64(b.Min.X)+margin, float64(b.Max.X)-margin
+		y0, y1 := float64(b.Min.Y+b.Max.Y)/2, float64(b.Max.Y)-margin
+
+		points := make([][2]float64, len(values))
+		for i, v := range values {
+			points[i] = [2]float64{
+				x0 + (x1-x0)*float64(i)/float64(len(values)-1),
+				y1 - (y1-y0)*(v-lo)/(hi-lo),
+			}
+		}
+
+		width := scale(dst, 2) / 2
+		fill(dst, func(x, y float64) bool {
+			for i := 1; i < len(points); i++ {
+				if distanceToSegment(x, y, points[i-1], points[i]) <= width {
+					return true
+				}
+			}
+			return false
+		}, c)
+	})
+}
+
+const samples = 4
+
+// fill paints the area where cover returns true with anti-aliasing.
+func fill(dst draw.Image, cover func(x, y float64) bool, c color.Color) {
+	b := dst.Bounds()
+	mask := image.NewAlpha(b)
+	for py := b.Min.Y; py < b.Max.Y; py++ {
+		for px := b.Min.X; px < b.Max.X; px++ {
+			n := 0
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					if cover(float64(px)+(float64(sx)+0.5)/samples, float64(py)+(float64(sy)+0.5)/samples) {
+						n++
+					}
+				}
+			}
+			mask.SetAlpha(px, py, color.Alpha{A: uint8(n * 0xff / (samples * samples))})
+		}
+	}
+	draw.DrawMask(dst, b, image.NewUniform(c), image.Point{}, mask, b.Min, draw.Over)
+}
+
+func distanceToSegment(x, y float64, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	t := ((x-a[0])*dx + (y-a[1])*dy) / (dx*dx + dy*dy)
+	t = clamp(t)
+	return math.Hypot(x-(a[0]+t*dx), y-(a[1]+t*dy))
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
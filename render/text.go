@@ -0,0 +1,136 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	streamdeck "github.com/morikuni/go-stream-deck-sdk"
+)
+
+var (
+	fontRegular    = mustParseFont(goregular.TTF)
+	fontBold       = mustParseFont(gobold.TTF)
+	fontItalic     = mustParseFont(goitalic.TTF)
+	fontBoldItalic = mustParseFont(gobolditalic.TTF)
+)
+
+func mustParseFont(ttf []byte) *opentype.Font {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// TextStyle is the style of text. The text is drawn with the embedded Go fonts.
+type TextStyle struct {
+	// Size is the font size in points on a 72px key. It is scaled with the image.
+	Size      float64
+	Color     color.Color
+	Alignment streamdeck.Alignment
+	Bold      bool
+	Italic    bool
+	Underline bool
+}
+
+// TextStyleFromTitleParameters returns the style matching the title parameters
+// set by the user. The font family is not respected because only the embedded
+// fonts are available.
+func TextStyleFromTitleParameters(tp streamdeck.TitleParameters) TextStyle {
+	style := TextStyle{
+		Size:      float64(tp.FontSize),
+		Color:     color.White,
+		Alignment: tp.TitleAlignment,
+		Bold:      strings.Contains(tp.FontStyle, "Bold"),
+		Italic:    strings.Contains(tp.FontStyle, "Italic"),
+		Underline: tp.FontUnderline,
+	}
+	if c, ok := Color(tp.TitleColor); ok {
+		style.Color = c
+	}
+	return style
+}
+
+// Text draws the text in the style. Lines are separated by "\n" and
+// centered horizontally.
+func Text(s string, style TextStyle) Layer {
+	return LayerFunc(func(dst draw.Image) {
+		drawText(dst, dst.Bounds(), s, style)
+	})
+}
+
+func drawText(dst draw.Image, area image.Rectangle, s string, style TextStyle) {
+	if style.Size <= 0 {
+		style.Size = 12
+	}
+	if style.Color == nil {
+		style.Color = color.White
+	}
+
+	f := fontRegular
+	switch {
+	case style.Bold && style.Italic:
+		f = fontBoldItalic
+	case style.Bold:
+		f = fontBold
+	case style.Italic:
+		f = fontItalic
+	}
+
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    scale(dst, style.Size),
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		// never happens because the options are valid.
+		panic(err)
+	}
+	defer face.Close()
+
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(style.Color),
+		Face: face,
+	}
+
+	metrics := face.Metrics()
+	lines := strings.Split(s, "\n")
+	lineHeight := metrics.Ascent + metrics.Descent
+	height := lineHeight.Mul(fixed.I(len(lines)))
+
+	var top fixed.Int26_6
+	margin := fixed.I(int(scale(dst, 4)))
+	switch style.Alignment {
+	case streamdeck.AlignmentTop:
+		top = fixed.I(area.Min.Y) + margin
+	case streamdeck.AlignmentBottom:
+		top = fixed.I(area.Max.Y) - margin - height
+	default:
+		top = (fixed.I(area.Min.Y+area.Max.Y) - height) / 2
+	}
+
+	for i, line := range lines {
+		width := d.MeasureString(line)
+		x := (fixed.I(area.Min.X+area.Max.X) - width) / 2
+		y := top + lineHeight.Mul(fixed.I(i)) + metrics.Ascent
+		d.Dot = fixed.Point26_6{X: x, Y: y}
+		d.DrawString(line)
+
+		if style.Underline {
+			thickness := fixed.I(int(scale(dst, 1)) + 1)
+			r := image.Rect(x.Floor(), (y + metrics.Descent/2).Floor(), (x + width).Ceil(), (y + metrics.Descent/2 + thickness).Ceil())
+			draw.Draw(dst, r, d.Src, image.Point{}, draw.Over)
+		}
+	}
+}
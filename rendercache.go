@@ -0,0 +1,71 @@
+package streamdeck
+
+import (
+	"sync"
+)
+
+// renderCache remembers the last title and image sent to each instance
+// to skip sending the same content again.
+type renderCache struct {
+	mu     sync.Mutex
+	values map[renderKey]string
+}
+
+type renderKey struct {
+	context InstanceID
+	event   string
+	target  Target
+	state   int
+}
+
+func newRenderCache() *renderCache {
+	return &renderCache{values: make(map[renderKey]string)}
+}
+
+func (c *renderCache) has(key renderKey, value string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v, ok := c.values[key]
+	return ok && v == value
+}
+
+func (c *renderCache) set(key renderKey, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the content for a target overwrites the one for overlapping targets.
+	for _, target := range []Target{TargetBoth, TargetHardware, TargetSoftware} {
+		k := key
+		k.target = target
+		delete(c.values, k)
+	}
+	c.values[key] = value
+}
+
+func (c *renderCache) remove(key renderKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.values, key)
+}
+
+func (c *renderCache) invalidate(ids ...InstanceID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(ids) == 0 {
+		c.values = make(map[renderKey]string)
+		return
+	}
+
+	invalid := make(map[InstanceID]bool, len(ids))
+	for _, id := range ids {
+		invalid[id] = true
+	}
+	for k := range c.values {
+		if invalid[k.context] {
+			delete(c.values, k)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package streamdeck
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when a scheduled function runs next.
+type Schedule interface {
+	// Next returns the next time after t.
+	Next(t time.Time) time.Time
+}
+
+// ScheduleFunc is a function implementing Schedule.
+type ScheduleFunc func(t time.Time) time.Time
+
+func (f ScheduleFunc) Next(t time.Time) time.Time {
+	return f(t)
+}
+
+// Every runs at the interval from the time the schedule is started or resumed.
+func Every(d time.Duration) Schedule {
+	return ScheduleFunc(func(t time.Time) time.Time {
+		return t.Add(d)
+	})
+}
+
+// EveryAligned runs at the multiples of the interval in the local time,
+// e.g. EveryAligned(time.Minute) runs at hh:mm:00 for a clock.
+func EveryAligned(d time.Duration) Schedule {
+	return ScheduleFunc(func(t time.Time) time.Time {
+		_, offset := t.Zone()
+		local := t.Add(time.Duration(offset) * time.Second)
+		return local.Truncate(d).Add(d).Add(-time.Duration(offset) * time.Second)
+	})
+}
+
+// cronSchedule is a schedule in the cron format.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+// ParseCron parses a schedule in the standard 5 fields cron format
+// "minute hour day-of-month month day-of-week".
+// Each field accepts *, numbers, ranges (1-5), lists (1,3) and steps (*/15).
+func ParseCron(spec string) (Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron spec: expected 5 fields but got %d: %q", len(fields), spec)
+	}
+
+	var s cronSchedule
+	for i, f := range []struct {
+		dst         *uint64
+		first, last int
+	}{
+		{&s.minute, 0, 59},
+		{&s.hour, 0, 23},
+		{&s.dom, 1, 31},
+		{&s.month, 1, 12},
+		{&s.dow, 0, 6},
+	} {
+		bits, err := parseCronField(fields[i], f.first, f.last)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron spec: %q: %w", spec, err)
+		}
+		*f.dst = bits
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+
+	return &s, nil
+}
+
+func parseCronField(field string, first, last int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step: %q", part)
+			}
+			rng = part[:i]
+		}
+
+		lo, hi := first, last
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			i := strings.Index(rng, "-")
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(rng[:i])
+			hi, err2 = strconv.Atoi(rng[i+1:])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range: %q", part)
+			}
+		default:
+			n, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value: %q", part)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = last
+			}
+		}
+		if lo < first || hi > last || lo > hi {
+			return 0, fmt.Errorf("out of range [%d, %d]: %q", first, last, part)
+		}
+
+		for n := lo; n <= hi; n += step {
+			bits |= 1 << uint(n)
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	// the schedule never matches if it doesn't match in 5 years, e.g. Feb 30.
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
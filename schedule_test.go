@@ -0,0 +1,59 @@
+package streamdeck
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	base := time.Date(2022, 2, 1, 10, 30, 15, 0, time.UTC) // Tuesday
+
+	for spec, want := range map[string]time.Time{
+		"* * * * *":     time.Date(2022, 2, 1, 10, 31, 0, 0, time.UTC),
+		"*/15 * * * *":  time.Date(2022, 2, 1, 10, 45, 0, 0, time.UTC),
+		"0 9-17 * * *":  time.Date(2022, 2, 1, 11, 0, 0, 0, time.UTC),
+		"0 0 1 * *":     time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC),
+		"0 12 * * 1,5":  time.Date(2022, 2, 4, 12, 0, 0, 0, time.UTC),
+		"0 0 13 * 5":    time.Date(2022, 2, 4, 0, 0, 0, 0, time.UTC), // either day of month or day of week
+		"30 10 29 2 *":  time.Date(2024, 2, 29, 10, 30, 0, 0, time.UTC),
+		"5/20 * * * *":  time.Date(2022, 2, 1, 10, 45, 0, 0, time.UTC),
+		"0 0 30 2 *":    {},
+		"0 0 * 1-3/2 *": time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 * * 0":     time.Date(2022, 2, 6, 0, 0, 0, 0, time.UTC),
+		"0 0 1-7/3 * *": time.Date(2022, 2, 4, 0, 0, 0, 0, time.UTC),
+		"0 */6 * * *":   time.Date(2022, 2, 1, 12, 0, 0, 0, time.UTC),
+	} {
+		s, err := ParseCron(spec)
+		noError(t, err)
+
+		got := s.Next(base)
+		if !got.Equal(want) {
+			t.Errorf("%q: want %v, got %v", spec, want, got)
+		}
+	}
+
+	for _, spec := range []string{
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		_, err := ParseCron(spec)
+		if err == nil {
+			t.Errorf("%q: error expected", spec)
+		}
+	}
+}
+
+func TestEveryAligned(t *testing.T) {
+	loc := time.FixedZone("JST", 9*60*60)
+	s := EveryAligned(time.Hour * 24)
+
+	got := s.Next(time.Date(2022, 2, 1, 10, 30, 15, 0, loc))
+	equal(t, got, time.Date(2022, 2, 2, 0, 0, 0, 0, loc))
+}
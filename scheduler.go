@@ -0,0 +1,195 @@
+package streamdeck
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ScheduledFunc is called by Scheduler for the instance.
+type ScheduledFunc func(ctx context.Context, id InstanceID)
+
+// Scheduler runs functions periodically while the instance is visible.
+// The functions for an instance are paused on WillDisappear, resumed on
+// WillAppear and rescheduled on SystemDidWakeUp to follow the wall clock.
+// Scheduler must receive the events via Middleware.
+type Scheduler struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// now and afterFunc are replaced in tests.
+	now       func() time.Time
+	afterFunc func(d time.Duration, f func()) timer
+
+	mu     sync.Mutex
+	jobs   map[InstanceID][]*job
+	hidden map[InstanceID]bool
+}
+
+type job struct {
+	id       InstanceID
+	schedule Schedule
+	fn       ScheduledFunc
+	timer    timer
+	canceled bool
+}
+
+func NewScheduler() *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		ctx:       ctx,
+		cancel:    cancel,
+		now:       time.Now,
+		afterFunc: afterFunc,
+		jobs:      make(map[InstanceID][]*job),
+		hidden:    make(map[InstanceID]bool),
+	}
+}
+
+// Schedule registers fn to run on the schedule for the instance.
+// It starts immediately unless the instance has disappeared.
+// The returned function cancels the registration.
+func (s *Scheduler) Schedule(id InstanceID, schedule Schedule, fn ScheduledFunc) (cancel func()) {
+	j := &job{id: id, schedule: schedule, fn: fn}
+
+	s.mu.Lock()
+	s.jobs[id] = append(s.jobs[id], j)
+	if !s.hidden[id] {
+		s.start(j)
+	}
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.stop(j)
+		j.canceled = true
+		jobs := s.jobs[id]
+		for i, jj := range jobs {
+			if jj == j {
+				s.jobs[id] = append(jobs[:i:i], jobs[i+1:]...)
+				break
+			}
+		}
+		if len(s.jobs[id]) == 0 {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// Cancel cancels all registrations for the instance.
+func (s *Scheduler) Cancel(id InstanceID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, j := range s.jobs[id] {
+		s.stop(j)
+		j.canceled = true
+	}
+	delete(s.jobs, id)
+}
+
+// Stop cancels all registrations and the context passed to the running functions.
+func (s *Scheduler) Stop() {
+	s.cancel()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, jobs := range s.jobs {
+		for _, j := range jobs {
+			s.stop(j)
+			j.canceled = true
+		}
+		delete(s.jobs, id)
+	}
+}
+
+// Middleware pauses and resumes the registrations following the visibility of the instances.
+func (s *Scheduler) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, ev Event) error {
+			switch ev := ev.(type) {
+			case *WillAppear:
+				s.setVisible(ev.Context, true)
+			case *WillDisappear:
+				s.setVisible(ev.Context, false)
+			case *SystemDidWakeUp:
+				s.realign()
+			}
+			return next.Handle(ctx, ev)
+		})
+	}
+}
+
+func (s *Scheduler) setVisible(id InstanceID, visible bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if visible {
+		delete(s.hidden, id)
+	} else {
+		s.hidden[id] = true
+	}
+
+	for _, j := range s.jobs[id] {
+		s.stop(j)
+		if visible {
+			s.start(j)
+		}
+	}
+}
+
+// realign reschedules all running registrations from the current time
+// because timers don't follow the wall clock while the system sleeps.
+func (s *Scheduler) realign() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, jobs := range s.jobs {
+		if s.hidden[id] {
+			continue
+		}
+		for _, j := range jobs {
+			s.stop(j)
+			s.start(j)
+		}
+	}
+}
+
+// start must be called with the lock.
+func (s *Scheduler) start(j *job) {
+	now := s.now()
+	next := j.schedule.Next(now)
+	if next.IsZero() {
+		return
+	}
+
+	var timer timer
+	timer = s.afterFunc(next.Sub(now), func() {
+		s.mu.Lock()
+		if j.canceled || j.timer != timer {
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+
+		j.fn(s.ctx, j.id)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !j.canceled && j.timer == timer {
+			s.start(j)
+		}
+	})
+	j.timer = timer
+}
+
+// stop must be called with the lock.
+func (s *Scheduler) stop(j *job) {
+	if j.timer != nil {
+		j.timer.Stop()
+		j.timer = nil
+	}
+}
@@ -0,0 +1,71 @@
+package streamdeck
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestScheduler(t *testing.T) (*Scheduler, *fakeClock, Handler) {
+	t.Helper()
+
+	clock := &fakeClock{}
+	s := NewScheduler()
+	s.now = clock.Now
+	s.afterFunc = clock.afterFunc
+	t.Cleanup(s.Stop)
+
+	h := Chain(HandlerFunc(func(ctx context.Context, ev Event) error {
+		return nil
+	}), s.Middleware())
+	return s, clock, h
+}
+
+func TestScheduler(t *testing.T) {
+	s, clock, h := newTestScheduler(t)
+
+	var ids []InstanceID
+	cancel := s.Schedule("context", Every(5*time.Millisecond), func(ctx context.Context, id InstanceID) {
+		ids = append(ids, id)
+	})
+
+	runs := func() int {
+		clock.advance(50 * time.Millisecond)
+		n := len(ids)
+		ids = nil
+		return n
+	}
+
+	equal(t, runs(), 10)
+	s.Schedule("other", Every(5*time.Millisecond), func(ctx context.Context, id InstanceID) {
+		equal(t, id, InstanceID("other"))
+	})
+	equal(t, runs(), 10)
+
+	noError(t, h.Handle(context.Background(), &WillDisappear{Context: "context"}))
+	equal(t, runs(), 0)
+
+	noError(t, h.Handle(context.Background(), &WillAppear{Context: "context"}))
+	clock.advance(5 * time.Millisecond)
+	equal(t, ids, []InstanceID{"context"})
+	ids = nil
+
+	cancel()
+	equal(t, runs(), 0)
+}
+
+func TestScheduler_Realign(t *testing.T) {
+	s, clock, h := newTestScheduler(t)
+
+	var got []time.Time
+	s.Schedule("context", EveryAligned(time.Minute), func(ctx context.Context, id InstanceID) {
+		got = append(got, clock.Now())
+	})
+
+	// the timer for 00:01:00 would fire at 00:01:30 after sleeping 30s.
+	clock.sleep(30 * time.Second)
+	noError(t, h.Handle(context.Background(), &SystemDidWakeUp{}))
+	clock.advance(30 * time.Second)
+
+	equal(t, got, []time.Time{fakeEpoch.Add(time.Minute)})
+}
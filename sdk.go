@@ -2,29 +2,123 @@ package streamdeck
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 )
 
 type SDK struct {
-	conn *Conn
+	connMu sync.RWMutex
+	conn   *Conn
 
-	debugLog bool
+	debugLog      bool
+	fileLogger    *FileLogger
+	mirrorLog     bool
+	recoverPanic  bool
+	crashReporter CrashReporter
+	renderCache   *renderCache
+
+	// settingsVersions is the current settings version of the instances
+	// whose settings are versioned by Settings.
+	settingsVersionsMu sync.Mutex
+	settingsVersions   map[InstanceID]int
+}
+
+type SDKOption sdkOption
+
+// WithFileLogger sets the logger used when a log cannot be sent via
+// Stream Deck API, e.g. after the connection is closed.
+func WithFileLogger(l *FileLogger) SDKOption {
+	return func(sdk *SDK) {
+		sdk.fileLogger = l
+	}
+}
+
+// WithMirrorLog makes the SDK write every log to the file logger in
+// addition to Stream Deck API.
+func WithMirrorLog() SDKOption {
+	return func(sdk *SDK) {
+		sdk.mirrorLog = true
+	}
+}
+
+// WithPanicRecovery makes Receive recover a panic in the handler and
+// keep receiving events instead of crashing the plugin.
+// The stack trace is logged and the alert is shown on the instance
+// that sent the event.
+func WithPanicRecovery() SDKOption {
+	return func(sdk *SDK) {
+		sdk.recoverPanic = true
+	}
+}
+
+// WithCrashReporter sets the function called with a recovered panic.
+// It enables panic recovery as well as WithPanicRecovery.
+func WithCrashReporter(r CrashReporter) SDKOption {
+	return func(sdk *SDK) {
+		sdk.recoverPanic = true
+		sdk.crashReporter = r
+	}
+}
+
+// WithRenderCache makes the SDK remember the last title and image sent to
+// each instance, and skip SetTitle and SetImage with the same content.
+// The cache for an instance is invalidated on WillAppear.
+func WithRenderCache() SDKOption {
+	return func(sdk *SDK) {
+		sdk.renderCache = newRenderCache()
+	}
+}
+
+type sdkOption func(*SDK)
+
+func NewSDK(conn *Conn, opts ...SDKOption) *SDK {
+	sdk := &SDK{conn: conn, debugLog: true}
+	for _, o := range opts {
+		o(sdk)
+	}
+	sdk.watchSendFailure(conn)
+	return sdk
+}
+
+// SetConn replaces the connection, e.g. after reconnecting to the Stream Deck application.
+// The render cache is invalidated because the application might have lost the state.
+func (sdk *SDK) SetConn(conn *Conn) {
+	sdk.connMu.Lock()
+	sdk.conn = conn
+	sdk.connMu.Unlock()
+
+	sdk.watchSendFailure(conn)
+
+	sdk.InvalidateRenderCache()
+}
+
+// InvalidateRenderCache forgets the title and image sent to the instances
+// so that the next SetTitle and SetImage are sent anyway.
+// All instances are invalidated if no instance is given.
+func (sdk *SDK) InvalidateRenderCache(ids ...InstanceID) {
+	if sdk.renderCache != nil {
+		sdk.renderCache.invalidate(ids...)
+	}
 }
 
-func NewSDK(conn *Conn) *SDK {
-	return &SDK{conn: conn, debugLog: true}
+func (sdk *SDK) getConn() *Conn {
+	sdk.connMu.RLock()
+	defer sdk.connMu.RUnlock()
+
+	return sdk.conn
 }
 
 func (sdk *SDK) OpenURL(url string) error {
-	return sdk.conn.Send(&OpenURL{
+	return sdk.getConn().Send(&OpenURL{
 		URL: url,
 	})
 }
 
 func (sdk *SDK) SetTitle(context InstanceID, title string, target Target, state int) error {
-	return sdk.conn.Send(&SetTitle{
+	return sdk.sendRender(renderKey{context, "setTitle", target, state}, title, &SetTitle{
 		Context: context,
 		Title:   title,
 		Target:  target,
@@ -33,7 +127,7 @@ func (sdk *SDK) SetTitle(context InstanceID, title string, target Target, state
 }
 
 func (sdk *SDK) SetImage(context InstanceID, img Image, target Target, state int) error {
-	return sdk.conn.Send(&SetImage{
+	return sdk.sendRender(renderKey{context, "setImage", target, state}, string(img), &SetImage{
 		Context: context,
 		Image:   img,
 		Target:  target,
@@ -41,29 +135,141 @@ func (sdk *SDK) SetImage(context InstanceID, img Image, target Target, state int
 	})
 }
 
+func (sdk *SDK) sendRender(key renderKey, value string, cmd Command) error {
+	if sdk.renderCache == nil {
+		return sdk.getConn().Send(cmd)
+	}
+
+	if sdk.renderCache.has(key, value) {
+		return nil
+	}
+
+	// the value is cached before sending so that a failure of the send
+	// queued under the rate limit invalidates it afterwards.
+	sdk.renderCache.set(key, value)
+	err := sdk.getConn().Send(cmd)
+	if err != nil {
+		sdk.renderCache.remove(key)
+		return err
+	}
+
+	return nil
+}
+
+// SetSettings saves the settings of the instance. The settings is marshaled into JSON.
+// If the settings of the instance are versioned by Settings with WithMigrations,
+// the current version is added to the settings marshaled into a JSON object.
+func (sdk *SDK) SetSettings(context InstanceID, settings interface{}) error {
+	if version, ok := sdk.settingsVersion(context); ok {
+		stamped, err := stampSettingsVersion(settings, version)
+		if err != nil {
+			return err
+		}
+		settings = stamped
+	}
+
+	return sdk.getConn().Send(&SetSettings{
+		Context:  context,
+		Settings: settings,
+	})
+}
+
+func (sdk *SDK) settingsVersion(context InstanceID) (int, bool) {
+	sdk.settingsVersionsMu.Lock()
+	defer sdk.settingsVersionsMu.Unlock()
+
+	v, ok := sdk.settingsVersions[context]
+	return v, ok
+}
+
+func (sdk *SDK) setSettingsVersion(context InstanceID, version int) {
+	sdk.settingsVersionsMu.Lock()
+	defer sdk.settingsVersionsMu.Unlock()
+
+	if sdk.settingsVersions == nil {
+		sdk.settingsVersions = make(map[InstanceID]int)
+	}
+	sdk.settingsVersions[context] = version
+}
+
+func (sdk *SDK) deleteSettingsVersion(context InstanceID) {
+	sdk.settingsVersionsMu.Lock()
+	defer sdk.settingsVersionsMu.Unlock()
+
+	delete(sdk.settingsVersions, context)
+}
+
+// GetSettings requests the settings of the instance, which are delivered by DidReceiveSettings.
+func (sdk *SDK) GetSettings(context InstanceID) error {
+	return sdk.getConn().Send(&GetSettings{
+		Context: context,
+	})
+}
+
+// SetGlobalSettings saves the settings of the plugin. The settings is marshaled into JSON.
+func (sdk *SDK) SetGlobalSettings(settings interface{}) error {
+	return sdk.getConn().Send(&SetGlobalSettings{
+		Settings: settings,
+	})
+}
+
+// GetGlobalSettings requests the settings of the plugin, which are delivered by DidReceiveGlobalSettings.
+func (sdk *SDK) GetGlobalSettings() error {
+	return sdk.getConn().Send(&GetGlobalSettings{})
+}
+
 func (sdk *SDK) ShowAlert(context InstanceID) error {
-	return sdk.conn.Send(&ShowAlert{
+	return sdk.getConn().Send(&ShowAlert{
 		Context: context,
 	})
 }
 
 func (sdk *SDK) ShowOK(context InstanceID) error {
-	return sdk.conn.Send(&ShowOK{
+	return sdk.getConn().Send(&ShowOK{
 		Context: context,
 	})
 }
 
 func (sdk *SDK) Log(a ...interface{}) {
 	s := fmt.Sprintln(a...)
-	_ = sdk.conn.Send(&LogMessage{
-		Message: s[:len(s)-1],
-	})
+	sdk.log(s[:len(s)-1], false)
 }
 
 // Logf prints formatted log via Stream Deck API.
 func (sdk *SDK) Logf(format string, a ...interface{}) {
-	_ = sdk.conn.Send(&LogMessage{
-		Message: fmt.Sprintf(format, a...),
+	sdk.log(fmt.Sprintf(format, a...), false)
+}
+
+// log sends the message via Stream Deck API. The message is written to
+// the file logger too if sending failed, mirroring is enabled or toFile is true.
+func (sdk *SDK) log(msg string, toFile bool) {
+	err := sdk.getConn().Send(&LogMessage{
+		Message: msg,
+	})
+	if sdk.fileLogger != nil && (err != nil || sdk.mirrorLog || toFile) {
+		sdk.fileLogger.Log(msg)
+	}
+}
+
+// watchSendFailure handles a command failed to be sent asynchronously
+// under the rate limit, because Send does not return the error in that case.
+// A log is written to the file logger, and the render cache of the instance
+// is invalidated so that the title or image is sent again.
+func (sdk *SDK) watchSendFailure(conn *Conn) {
+	if conn == nil {
+		return
+	}
+	conn.setSendFailure(func(payload *commandPayload) {
+		switch payload.Event {
+		case "logMessage":
+			var lm LogMessage
+			// the log has already been written if mirroring is enabled.
+			if sdk.fileLogger != nil && !sdk.mirrorLog && json.Unmarshal(payload.Payload, &lm) == nil {
+				sdk.fileLogger.Log(lm.Message)
+			}
+		case "setTitle", "setImage":
+			sdk.InvalidateRenderCache(InstanceID(payload.Context))
+		}
 	})
 }
 
@@ -81,7 +287,7 @@ func (sdk *SDK) debugf(format string, a ...interface{}) {
 
 func (sdk *SDK) Receive(ctx context.Context, h Handler) error {
 	for {
-		ev, err := sdk.conn.Receive()
+		ev, err := sdk.getConn().Receive()
 		if errors.Is(err, io.EOF) {
 			return fmt.Errorf("stop due to EOF: %w", err)
 		}
@@ -92,13 +298,55 @@ func (sdk *SDK) Receive(ctx context.Context, h Handler) error {
 
 		sdk.debugf("[DEBUG] go-stream-deck-sdk: received: %#v", ev)
 
-		err = h.Handle(ctx, ev)
-		if err != nil {
+		if ev, ok := ev.(*WillAppear); ok {
+			sdk.InvalidateRenderCache(ev.Context)
+		}
+
+		err = sdk.handle(ctx, h, ev)
+
+		// the version is forgotten after the handler which might save the settings.
+		if ev, ok := ev.(*WillDisappear); ok {
+			sdk.deleteSettingsVersion(ev.Context)
+		}
+
+		if err != nil && !errors.Is(err, ErrPanic) {
 			return err
 		}
 	}
 }
 
+func (sdk *SDK) handle(ctx context.Context, h Handler, ev Event) error {
+	if sdk.recoverPanic {
+		h = Recover(sdk)(h)
+	}
+
+	return h.Handle(ctx, ev)
+}
+
+func (sdk *SDK) reportCrash(ctx context.Context, report *CrashReport) {
+	// always leave the crash in the file because the plugin might not survive.
+	sdk.log(fmt.Sprintf("go-stream-deck-sdk: panic on %T: %v\n%s", report.Event, report.Recovered, report.Stack), true)
+
+	if id, ok := instanceIDOf(report.Event); ok {
+		_ = sdk.ShowAlert(id)
+	}
+
+	if sdk.crashReporter != nil {
+		sdk.crashReporter(ctx, report)
+	}
+}
+
+// CrashReport is the information about a panic recovered in a handler.
+type CrashReport struct {
+	Event     Event
+	Recovered interface{}
+	Stack     []byte
+}
+
+// CrashReporter is called with a panic recovered in a handler
+// to report the crash to an external service.
+type CrashReporter func(ctx context.Context, report *CrashReport)
+
 type Handler interface {
 	Handle(ctx context.Context, ev Event) error
 }
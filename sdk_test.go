@@ -0,0 +1,164 @@
+package streamdeck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morikuni/go-stream-deck-sdk/streamdecktest"
+)
+
+func newTestSDK(t *testing.T, opts ...SDKOption) (*SDK, *streamdecktest.Server) {
+	t.Helper()
+
+	srv := streamdecktest.NewServer()
+	t.Cleanup(srv.Close)
+
+	conn, err := Dial(WithPort(srv.Port()), WithPluginUUID("pluginUUID"), WithRegisterEvent("registerPlugin"))
+	noError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = srv.Registration(time.Second)
+	noError(t, err)
+
+	return NewSDK(conn, opts...), srv
+}
+
+func nextCommand(t *testing.T, srv *streamdecktest.Server) *streamdecktest.Command {
+	t.Helper()
+
+	cmd, err := srv.NextCommand(time.Second)
+	noError(t, err)
+	return cmd
+}
+
+func TestSDK_Receive_PanicRecovery(t *testing.T) {
+	var reports []*CrashReport
+	sdk, srv := newTestSDK(t, WithCrashReporter(func(ctx context.Context, report *CrashReport) {
+		reports = append(reports, report)
+	}))
+	sdk.debugLog = false
+
+	errStop := errors.New("stop")
+	done := make(chan error, 1)
+	go func() {
+		done <- sdk.Receive(context.Background(), HandlerFunc(func(ctx context.Context, ev Event) error {
+			switch ev.(type) {
+			case *KeyDown:
+				panic("boom")
+			case *KeyUp:
+				return errStop
+			}
+			return nil
+		}))
+	}()
+
+	noError(t, srv.SendEvent(keyDownJSON))
+
+	cmd := nextCommand(t, srv)
+	equal(t, cmd.Event, "logMessage")
+	var log LogMessage
+	noError(t, json.Unmarshal(cmd.Payload, &log))
+	if !strings.HasPrefix(log.Message, "go-stream-deck-sdk: panic on *streamdeck.KeyDown: boom\n") {
+		t.Fatal("unexpected log:", log.Message)
+	}
+
+	cmd = nextCommand(t, srv)
+	equal(t, cmd.Event, "showAlert")
+	equal(t, cmd.Context, "context")
+
+	// the SDK keeps receiving events after the panic.
+	noError(t, srv.SendEvent(keyUpJSON))
+	select {
+	case err := <-done:
+		if !errors.Is(err, errStop) {
+			t.Fatal("unexpected error:", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	equal(t, len(reports), 1)
+	equal(t, reports[0].Recovered, "boom")
+	if _, ok := reports[0].Event.(*KeyDown); !ok {
+		t.Fatalf("unexpected event: %T", reports[0].Event)
+	}
+}
+
+func TestSDK_Receive_DeleteSettingsVersion(t *testing.T) {
+	sdk, srv := newTestSDK(t)
+	sdk.debugLog = false
+	sdk.setSettingsVersion("context", 2)
+
+	errStop := errors.New("stop")
+	done := make(chan error, 1)
+	go func() {
+		done <- sdk.Receive(context.Background(), HandlerFunc(func(ctx context.Context, ev Event) error {
+			if _, ok := ev.(*KeyUp); ok {
+				return errStop
+			}
+			return nil
+		}))
+	}()
+
+	noError(t, srv.SendEvent(willDisappearJSON))
+	noError(t, srv.SendEvent(keyUpJSON))
+	select {
+	case err := <-done:
+		if !errors.Is(err, errStop) {
+			t.Fatal("unexpected error:", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout")
+	}
+
+	if _, ok := sdk.settingsVersion("context"); ok {
+		t.Fatal("the settings version must be deleted on WillDisappear")
+	}
+}
+
+func TestSDK_RenderCache(t *testing.T) {
+	sdk, srv := newTestSDK(t, WithRenderCache())
+	sdk.debugLog = false
+
+	expectTitle := func(want string) {
+		t.Helper()
+
+		cmd := nextCommand(t, srv)
+		equal(t, cmd.Event, "setTitle")
+		var got SetTitle
+		noError(t, json.Unmarshal(cmd.Payload, &got))
+		equal(t, got.Title, want)
+	}
+
+	noError(t, sdk.SetTitle("context", "a", TargetBoth, 0))
+	noError(t, sdk.SetTitle("context", "a", TargetBoth, 0))
+	noError(t, sdk.SetTitle("context", "b", TargetHardware, 0))
+	noError(t, sdk.SetTitle("context", "a", TargetBoth, 0))
+	expectTitle("a")
+	expectTitle("b")
+	expectTitle("a")
+
+	go func() {
+		_ = sdk.Receive(context.Background(), HandlerFunc(func(ctx context.Context, ev Event) error {
+			if ev, ok := ev.(*WillAppear); ok {
+				return sdk.SetTitle(ev.Context, "a", TargetBoth, 0)
+			}
+			return nil
+		}))
+	}()
+	noError(t, srv.SendEvent(willAppearJSON))
+	expectTitle("a")
+
+	sdk.InvalidateRenderCache()
+	noError(t, sdk.SetTitle("context", "a", TargetBoth, 0))
+	expectTitle("a")
+
+	_, err := srv.NextCommand(10 * time.Millisecond)
+	if err == nil {
+		t.Fatal("cached title must not be sent")
+	}
+}
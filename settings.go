@@ -0,0 +1,437 @@
+package streamdeck
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Settings decodes the settings of instances into T.
+//
+// The fields of T are configured by the struct tags below in addition to the json tag.
+//
+//	default:"value"        the value used when the field is absent.
+//	validate:"rules"       comma separated rules:
+//	                       required      the field must be present.
+//	                       min=n, max=n  the range of a number, or the length of a string or a slice.
+//	                       oneof=a b c   the allowed values separated by spaces.
+type Settings[T any] struct {
+	sdk        *SDK
+	migrations []Migration
+}
+
+type SettingsOption settingsOption
+
+// WithMigrations versions the settings. The migration at index i converts
+// the settings of version i into version i+1, so the current version is the
+// number of migrations. The version is saved in the settings with the key
+// SettingsVersionKey.
+//
+// The settings without the version are regarded as the ones saved before the
+// versioning was introduced, i.e. version 0, unless they are empty or the
+// instance has already had the settings of the current version in the process.
+// The latter is the case when a property inspector saves the settings without
+// keeping the version, and such settings are saved again with the version.
+// SDK.SetSettings adds the current version to the settings of such instances too.
+func WithMigrations(migrations ...Migration) SettingsOption {
+	return func(cfg *settingsConfig) {
+		cfg.migrations = migrations
+	}
+}
+
+type settingsOption func(*settingsConfig)
+
+type settingsConfig struct {
+	migrations []Migration
+}
+
+// Migration converts the settings into the next version.
+type Migration func(settings map[string]interface{}) error
+
+// SettingsVersionKey is the key of the settings version saved by WithMigrations.
+const SettingsVersionKey = "settingsVersion"
+
+func NewSettings[T any](sdk *SDK, opts ...SettingsOption) *Settings[T] {
+	var cfg settingsConfig
+	for _, o := range opts {
+		o(&cfg)
+	}
+	return &Settings[T]{sdk: sdk, migrations: cfg.migrations}
+}
+
+// Get decodes the settings of the event. If the settings are invalid, it shows
+// the alert on the instance, logs the reason and returns the error.
+func (s *Settings[T]) Get(ev Event) (*T, error) {
+	raw, ok := settingsOf(ev)
+	if !ok {
+		return nil, fmt.Errorf("%T has no settings", ev)
+	}
+
+	if len(s.migrations) > 0 {
+		id, hasID := instanceIDOf(ev)
+		current := false
+		if hasID {
+			v, ok := s.sdk.settingsVersion(id)
+			current = ok && v == len(s.migrations)
+		}
+
+		migrated, changed, err := migrateSettings(raw, s.migrations, current)
+		if err != nil {
+			s.reportInvalid(ev, err)
+			return nil, err
+		}
+		if hasID {
+			s.sdk.setSettingsVersion(id, len(s.migrations))
+		}
+		if changed {
+			if hasID {
+				err = s.sdk.SetSettings(id, migrated)
+				if err != nil {
+					return nil, fmt.Errorf("failed to save migrated settings: %w", err)
+				}
+			}
+			raw = migrated
+		}
+	}
+
+	v, err := DecodeSettings[T](raw)
+	if err != nil {
+		s.reportInvalid(ev, err)
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (s *Settings[T]) reportInvalid(ev Event, err error) {
+	s.sdk.Logf("go-stream-deck-sdk: %s: invalid settings: %v", describeEvent(ev), err)
+	if id, ok := instanceIDOf(ev); ok {
+		_ = s.sdk.ShowAlert(id)
+	}
+}
+
+// DecodeSettings decodes the raw settings into T applying the default values and
+// validating the fields as described in Settings.
+func DecodeSettings[T any](raw json.RawMessage) (*T, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("settings must be a struct: %T", v)
+	}
+
+	err := applyDefaults(rv)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) > 0 && string(raw) != "null" {
+		err = json.Unmarshal(raw, &v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode settings: %w", err)
+		}
+	}
+
+	var errs SettingsError
+	validateSettings(rv, raw, "", &errs)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return &v, nil
+}
+
+// migrateSettings applies the migrations after the version of the settings.
+// It reports whether the settings are migrated or stamped with the version.
+// The settings without the version are regarded as the current version
+// if current is true, or version 0 otherwise.
+func migrateSettings(raw json.RawMessage, migrations []Migration, current bool) (json.RawMessage, bool, error) {
+	settings := map[string]interface{}{}
+	if len(raw) > 0 && string(raw) != "null" {
+		err := json.Unmarshal(raw, &settings)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to decode settings: %w", err)
+		}
+	}
+
+	latest := len(migrations)
+	version := latest
+	if len(settings) > 0 && !current {
+		version = 0
+	}
+	if v, ok := settings[SettingsVersionKey]; ok {
+		f, ok := v.(float64)
+		if !ok || f != float64(int(f)) {
+			return nil, false, fmt.Errorf("invalid settings version: %v", v)
+		}
+		version = int(f)
+	}
+
+	switch {
+	case version > latest:
+		return nil, false, fmt.Errorf("settings version %d is newer than %d", version, latest)
+	case version == latest && settings[SettingsVersionKey] != nil:
+		return raw, false, nil
+	}
+
+	for i := version; i < latest; i++ {
+		err := migrations[i](settings)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to migrate settings from version %d: %w", i, err)
+		}
+	}
+	settings[SettingsVersionKey] = latest
+
+	migrated, err := json.Marshal(settings)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode migrated settings: %w", err)
+	}
+	return migrated, true, nil
+}
+
+// stampSettingsVersion adds the version to the settings marshaled into a JSON object
+// unless they have the version. The other settings are returned as is.
+func stampSettingsVersion(settings interface{}, version int) (interface{}, error) {
+	bs, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+
+	var m map[string]json.RawMessage
+	if json.Unmarshal(bs, &m) != nil || m == nil {
+		return settings, nil
+	}
+	if _, ok := m[SettingsVersionKey]; ok {
+		return settings, nil
+	}
+	m[SettingsVersionKey] = json.RawMessage(strconv.Itoa(version))
+	return m, nil
+}
+
+// SettingsError is the list of invalid fields.
+type SettingsError []*FieldError
+
+func (e SettingsError) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// FieldError is the reason why a field is invalid.
+type FieldError struct {
+	// Field is the path to the field joined by dots, e.g. "server.port".
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+type settingsField struct {
+	value reflect.Value
+	field reflect.StructField
+	name  string
+}
+
+// settingsFields returns the fields of the struct encoded in JSON.
+// The fields of an embedded struct are flattened.
+func settingsFields(rv reflect.Value) []settingsField {
+	var fields []settingsField
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			n := strings.Split(tag, ",")[0]
+			if n == "-" {
+				continue
+			}
+			if n != "" {
+				name = n
+			} else if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				fields = append(fields, settingsFields(rv.Field(i))...)
+				continue
+			}
+		} else if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			fields = append(fields, settingsFields(rv.Field(i))...)
+			continue
+		}
+
+		fields = append(fields, settingsField{rv.Field(i), f, name})
+	}
+	return fields
+}
+
+func applyDefaults(rv reflect.Value) error {
+	for _, f := range settingsFields(rv) {
+		if f.value.Kind() == reflect.Struct {
+			err := applyDefaults(f.value)
+			if err != nil {
+				return err
+			}
+		}
+
+		def, ok := f.field.Tag.Lookup("default")
+		if !ok {
+			continue
+		}
+		err := setDefault(f.value, def)
+		if err != nil {
+			return fmt.Errorf("invalid default value of %s: %w", f.field.Name, err)
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setDefault(v reflect.Value, def string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(def)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(def)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(def)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(def, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(def, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(def, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return json.Unmarshal([]byte(def), v.Addr().Interface())
+	}
+	return nil
+}
+
+func validateSettings(rv reflect.Value, raw json.RawMessage, prefix string, errs *SettingsError) {
+	var present map[string]json.RawMessage
+	_ = json.Unmarshal(raw, &present)
+
+	for _, f := range settingsFields(rv) {
+		path := prefix + f.name
+		fieldRaw, ok := lookupJSONKey(present, f.name)
+
+		if f.value.Kind() == reflect.Struct && f.value.Type() != durationType {
+			validateSettings(f.value, fieldRaw, path+".", errs)
+		}
+
+		rules, hasRules := f.field.Tag.Lookup("validate")
+		if !hasRules {
+			continue
+		}
+		for _, rule := range strings.Split(rules, ",") {
+			name, arg := rule, ""
+			if i := strings.Index(rule, "="); i >= 0 {
+				name, arg = rule[:i], rule[i+1:]
+			}
+
+			msg := checkRule(f.value, name, arg, ok)
+			if msg != "" {
+				*errs = append(*errs, &FieldError{Field: path, Message: msg})
+				break
+			}
+		}
+	}
+}
+
+// lookupJSONKey finds the key case-insensitively as encoding/json does.
+func lookupJSONKey(m map[string]json.RawMessage, key string) (json.RawMessage, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func checkRule(v reflect.Value, rule, arg string, present bool) string {
+	switch rule {
+	case "required":
+		if !present {
+			return "required"
+		}
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Sprintf("invalid rule %s=%s", rule, arg)
+		}
+		n, isLen, ok := measure(v)
+		if !ok {
+			return fmt.Sprintf("rule %s is not applicable to %s", rule, v.Type())
+		}
+		what := "value"
+		if isLen {
+			what = "length"
+		}
+		if rule == "min" && n < limit {
+			return fmt.Sprintf("%s must be at least %s", what, arg)
+		}
+		if rule == "max" && n > limit {
+			return fmt.Sprintf("%s must be at most %s", what, arg)
+		}
+	case "oneof":
+		s := fmt.Sprint(v.Interface())
+		for _, o := range strings.Fields(arg) {
+			if s == o {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of [%s]", arg)
+	default:
+		return fmt.Sprintf("unknown rule %s", rule)
+	}
+	return ""
+}
+
+// measure returns the value of a number or the length of a string or a slice.
+func measure(v reflect.Value) (n float64, isLen bool, ok bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, true
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), true, true
+	default:
+		return 0, false, false
+	}
+}
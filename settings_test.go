@@ -0,0 +1,156 @@
+package streamdeck
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testSettings struct {
+	Name     string        `json:"name" validate:"required,max=5"`
+	Interval time.Duration `json:"interval" default:"1s"`
+	Count    int           `json:"count" default:"3" validate:"min=1,max=10"`
+	Mode     string        `json:"mode" default:"clock" validate:"oneof=clock timer"`
+	Server   struct {
+		Port int `json:"port" default:"80" validate:"min=1"`
+	} `json:"server"`
+}
+
+func TestDecodeSettings(t *testing.T) {
+	for name, tt := range map[string]struct {
+		json string
+
+		want    *testSettings
+		wantErr SettingsError
+	}{
+		"defaults": {
+			`{"name": "foo"}`,
+			func() *testSettings {
+				s := &testSettings{Name: "foo", Interval: time.Second, Count: 3, Mode: "clock"}
+				s.Server.Port = 80
+				return s
+			}(),
+			nil,
+		},
+		"overwrite defaults": {
+			`{"name": "foo", "interval": 5000000000, "count": 10, "mode": "timer", "server": {"port": 8080}}`,
+			func() *testSettings {
+				s := &testSettings{Name: "foo", Interval: 5 * time.Second, Count: 10, Mode: "timer"}
+				s.Server.Port = 8080
+				return s
+			}(),
+			nil,
+		},
+		"invalid": {
+			`{"name": "foobar", "count": 0, "mode": "alarm", "server": {"port": 0}}`,
+			nil,
+			SettingsError{
+				{Field: "name", Message: "length must be at most 5"},
+				{Field: "count", Message: "value must be at least 1"},
+				{Field: "mode", Message: "must be one of [clock timer]"},
+				{Field: "server.port", Message: "value must be at least 1"},
+			},
+		},
+		"required": {
+			`{}`,
+			nil,
+			SettingsError{
+				{Field: "name", Message: "required"},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := DecodeSettings[testSettings](json.RawMessage(tt.json))
+			if tt.wantErr != nil {
+				var se SettingsError
+				if !errors.As(err, &se) {
+					t.Fatal("SettingsError expected:", err)
+				}
+				equal(t, se, tt.wantErr)
+				return
+			}
+
+			noError(t, err)
+			equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestSettings_Get(t *testing.T) {
+	sdk, srv := newTestSDK(t)
+	settings := NewSettings[testSettings](sdk)
+
+	_, err := settings.Get(&KeyDown{Context: "context", Settings: json.RawMessage(`{}`)})
+	if err == nil {
+		t.Fatal("error expected")
+	}
+
+	cmd := nextCommand(t, srv)
+	equal(t, cmd.Event, "logMessage")
+	cmd = nextCommand(t, srv)
+	equal(t, cmd.Event, "showAlert")
+	equal(t, cmd.Context, "context")
+
+	got, err := settings.Get(&WillAppear{Context: "context", Settings: json.RawMessage(`{"name": "foo"}`)})
+	noError(t, err)
+	equal(t, got.Name, "foo")
+}
+
+func TestSettings_Migrations(t *testing.T) {
+	type v2 struct {
+		Title string `json:"title"`
+		Count int    `json:"count"`
+	}
+
+	sdk, srv := newTestSDK(t)
+	settings := NewSettings[v2](sdk, WithMigrations(
+		func(s map[string]interface{}) error {
+			s["title"] = s["name"]
+			delete(s, "name")
+			return nil
+		},
+		func(s map[string]interface{}) error {
+			s["count"] = 2
+			return nil
+		},
+	))
+
+	got, err := settings.Get(&WillAppear{Context: "context", Settings: json.RawMessage(`{"name": "foo"}`)})
+	noError(t, err)
+	equal(t, got, &v2{Title: "foo", Count: 2})
+
+	cmd := nextCommand(t, srv)
+	equal(t, cmd.Event, "setSettings")
+	equal(t, cmd.Context, "context")
+	equalJSON(t, cmd.Payload, []byte(`{"title": "foo", "count": 2, "settingsVersion": 2}`))
+
+	got, err = settings.Get(&KeyDown{Context: "context", Settings: json.RawMessage(`{"title": "bar", "count": 3, "settingsVersion": 2}`)})
+	noError(t, err)
+	equal(t, got, &v2{Title: "bar", Count: 3})
+
+	_, err = srv.NextCommand(10 * time.Millisecond)
+	if err == nil {
+		t.Fatal("settings of the current version must not be saved")
+	}
+
+	_, err = settings.Get(&KeyDown{Context: "context", Settings: json.RawMessage(`{"settingsVersion": 3}`)})
+	if err == nil {
+		t.Fatal("error expected for a newer version")
+	}
+	equal(t, nextCommand(t, srv).Event, "logMessage")
+	equal(t, nextCommand(t, srv).Event, "showAlert")
+
+	// a property inspector saved the current settings dropping the version.
+	got, err = settings.Get(&DidReceiveSettings{Context: "context", Settings: json.RawMessage(`{"title": "baz", "count": 4}`)})
+	noError(t, err)
+	equal(t, got, &v2{Title: "baz", Count: 4})
+
+	cmd = nextCommand(t, srv)
+	equal(t, cmd.Event, "setSettings")
+	equalJSON(t, cmd.Payload, []byte(`{"title": "baz", "count": 4, "settingsVersion": 2}`))
+
+	noError(t, sdk.SetSettings("context", &v2{Title: "qux", Count: 5}))
+	cmd = nextCommand(t, srv)
+	equalJSON(t, cmd.Payload, []byte(`{"title": "qux", "count": 5, "settingsVersion": 2}`))
+}
@@ -0,0 +1,136 @@
+// Package streamdecktest provides a fake Stream Deck application
+// to test plugins without the real application.
+package streamdecktest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// Command is a command received from the plugin.
+type Command struct {
+	Event   string          `json:"event"`
+	Context string          `json:"context"`
+	Action  string          `json:"action"`
+	Device  string          `json:"device"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Server is a websocket server that behaves as the Stream Deck application.
+// It accepts a connection at a time. The next connection replaces the current one.
+type Server struct {
+	srv *httptest.Server
+
+	mu            sync.Mutex
+	conn          *websocket.Conn
+	registrations chan map[string]string
+	commands      chan *Command
+}
+
+func NewServer() *Server {
+	s := &Server{
+		registrations: make(chan map[string]string, 16),
+		commands:      make(chan *Command, 1024),
+	}
+	s.srv = httptest.NewServer(websocket.Handler(s.serve))
+	return s
+}
+
+// Port returns the port to pass to streamdeck.WithPort.
+func (s *Server) Port() string {
+	_, port, _ := net.SplitHostPort(s.srv.Listener.Addr().String())
+	return port
+}
+
+func (s *Server) serve(conn *websocket.Conn) {
+	var reg map[string]string
+	err := websocket.JSON.Receive(conn, &reg)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.mu.Unlock()
+	s.registrations <- reg
+
+	for {
+		var cmd Command
+		err := websocket.JSON.Receive(conn, &cmd)
+		if err != nil {
+			return
+		}
+		s.commands <- &cmd
+	}
+}
+
+// Registration waits for the plugin to register itself and returns the registration message.
+func (s *Server) Registration(timeout time.Duration) (map[string]string, error) {
+	select {
+	case reg := <-s.registrations:
+		return reg, nil
+	case <-time.After(timeout):
+		return nil, errors.New("streamdecktest: timeout waiting for registration")
+	}
+}
+
+// NextCommand waits for a command sent from the plugin.
+func (s *Server) NextCommand(timeout time.Duration) (*Command, error) {
+	select {
+	case cmd := <-s.commands:
+		return cmd, nil
+	case <-time.After(timeout):
+		return nil, errors.New("streamdecktest: timeout waiting for a command")
+	}
+}
+
+// SendEvent sends an event to the plugin. The event is a JSON string or a value marshaled into JSON.
+func (s *Server) SendEvent(event interface{}) error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn == nil {
+		return errors.New("streamdecktest: plugin is not connected")
+	}
+
+	var err error
+	switch event := event.(type) {
+	case string:
+		err = websocket.Message.Send(conn, event)
+	case []byte:
+		err = websocket.Message.Send(conn, string(event))
+	default:
+		err = websocket.JSON.Send(conn, event)
+	}
+	if err != nil {
+		return fmt.Errorf("streamdecktest: failed to send an event: %w", err)
+	}
+
+	return nil
+}
+
+// Disconnect closes the current connection with the plugin.
+func (s *Server) Disconnect() error {
+	s.mu.Lock()
+	conn := s.conn
+	s.conn = nil
+	s.mu.Unlock()
+
+	if conn == nil {
+		return nil
+	}
+	return conn.Close()
+}
+
+func (s *Server) Close() {
+	_ = s.Disconnect()
+	s.srv.Close()
+}